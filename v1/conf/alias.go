@@ -0,0 +1,131 @@
+package conf
+
+import (
+	"sort"
+	"strings"
+)
+
+// RegisterAlias makes alias an alternative name for canonical, so old key
+// names keep working after a rename: getters, IsSet, InConfig and Unmarshal
+// resolve alias, and everything below it, to canonical, and Set and Unset
+// write to canonical. Config files still using the old name are honored as
+// well, canonical winning when both are defined. Aliases that would form a
+// cycle are ignored.
+func (c *Config) RegisterAlias(alias, canonical string) {
+	if alias == "" || canonical == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	if _, below := cutKey(canonical, alias, c.delimLocked()); below {
+		return
+	}
+	if c.load().canonical(canonical) == alias {
+		return
+	}
+	if c.keyAliases == nil {
+		c.keyAliases = make(map[string]string)
+	}
+	c.keyAliases[alias] = canonical
+	c.publishLocked()
+}
+
+type keyAlias struct {
+	alias     string
+	canonical string
+}
+
+// sortedAliasesLocked returns the registered aliases sorted by alias. The
+// caller must hold c.mu.
+func (c *Config) sortedAliasesLocked() []keyAlias {
+	if len(c.keyAliases) == 0 {
+		return nil
+	}
+	out := make([]keyAlias, 0, len(c.keyAliases))
+	for alias, canonical := range c.keyAliases {
+		if c.foldKeys {
+			alias, canonical = strings.ToLower(alias), strings.ToLower(canonical)
+		}
+		out = append(out, keyAlias{alias: alias, canonical: canonical})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].alias < out[j].alias })
+	return out
+}
+
+// cutKey reports whether key is prefix or below it, returning the rest of
+// key including its leading sep.
+func cutKey(key, prefix, sep string) (string, bool) {
+	if key == prefix {
+		return "", true
+	}
+	if strings.HasPrefix(key, prefix+sep) {
+		return key[len(prefix):], true
+	}
+	return "", false
+}
+
+// canonical follows the aliases of key, and of its parents, to its canonical
+// name, lower-cased when keys are case-insensitive.
+func (s *state) canonical(key string) string {
+	if s.fold {
+		key = strings.ToLower(key)
+	}
+	for range s.aliases {
+		changed := false
+		for _, a := range s.aliases {
+			if rest, ok := cutKey(key, a.alias, s.delim); ok {
+				key = a.canonical + rest
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return key
+}
+
+// aliased resolves key with fn under its canonical name, then under the
+// aliases of that name, for layers still using an old name.
+func (s *state) aliased(key string, fn func(string) (any, Source, bool)) (any, Source, bool) {
+	key = s.canonical(key)
+	if v, src, ok := fn(key); ok {
+		return v, src, true
+	}
+	for _, a := range s.aliases {
+		if rest, ok := cutKey(key, a.canonical, s.delim); ok {
+			if v, src, ok := fn(a.alias + rest); ok {
+				return v, src, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// canonicalMap returns m with the keys stored under an alias moved to their
+// canonical name, unless that name is already defined.
+func (s *state) canonicalMap(m map[string]any) map[string]any {
+	if len(s.aliases) == 0 || m == nil {
+		return m
+	}
+	flat := make(map[string]any)
+	flattenInto("", m, flat, s.delim)
+	out := make(map[string]any)
+	var renamed []string
+	for key, v := range flat {
+		if canon := s.canonical(key); canon != key {
+			renamed = append(renamed, key)
+			continue
+		}
+		setPath(out, strings.Split(key, s.delim), cloneValue(v))
+	}
+	sort.Strings(renamed)
+	for _, key := range renamed {
+		canon := s.canonical(key)
+		if _, ok := fetchValue(out, canon, s.delim); !ok {
+			setPath(out, strings.Split(canon, s.delim), cloneValue(flat[key]))
+		}
+	}
+	return out
+}
@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegisterAlias(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte("db:\n  host: legacy\nlog_level: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.RegisterAlias("db", "database")
+	c.RegisterAlias("loglevel", "log.level")
+	c.RegisterAlias("database", "db")
+	c.SetDefault("log.level", "info")
+	c.SetConfigFile(file)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetString("database.host"); got != "legacy" {
+		t.Fatalf("expected the old name in the file to be honored, got %q", got)
+	}
+	if got := c.GetString("db.host"); got != "legacy" || !c.IsSet("db.host") || !c.InConfig("database.host") {
+		t.Fatalf("expected the alias to resolve, got %q", got)
+	}
+	if got := c.GetString("loglevel"); got != "info" {
+		t.Fatalf("expected alias of a default, got %q", got)
+	}
+
+	c.Set("db.port", 5432)
+	if got := c.GetInt("database.port"); got != 5432 {
+		t.Fatalf("expected Set to write the canonical key, got %d", got)
+	}
+	var cfg struct {
+		Database struct {
+			Host string
+			Port int
+		}
+	}
+	if err := c.Unmarshal("", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Host != "legacy" || cfg.Database.Port != 5432 {
+		t.Fatalf("expected Unmarshal to resolve aliases, got %+v", cfg.Database)
+	}
+	for _, key := range c.AllKeys() {
+		if key == "db.host" {
+			t.Fatalf("expected keys under their canonical name, got %v", c.AllKeys())
+		}
+	}
+}
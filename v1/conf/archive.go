@@ -0,0 +1,154 @@
+package conf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ArchiveProvider is a Provider reading a bundle of config files from a zip,
+// tar or gzipped tar archive. The archive root must contain a manifest file
+// (manifest.yaml, manifest.json, ...) whose "files" list names the files to
+// merge, in order, later files overriding earlier ones:
+//
+//	files:
+//	  - base.yaml
+//	  - overlays/production.toml
+type ArchiveProvider struct {
+	Path    string
+	Decoder Decoder
+	// SHA256, when not empty, pins the archive to the listed hex encoded
+	// SHA-256 digests; any other archive is rejected with ErrHashMismatch.
+	SHA256 []string
+}
+
+// NewArchiveProvider returns a provider reading the archive at path and
+// decoding its files with dec, usually the Config it is added to.
+func NewArchiveProvider(path string, dec Decoder) *ArchiveProvider {
+	return &ArchiveProvider{Path: path, Decoder: dec}
+}
+
+// Name returns the path of the archive.
+func (p *ArchiveProvider) Name() string {
+	return p.Path
+}
+
+// Load reads the archive and merges the files listed by its manifest.
+func (p *ArchiveProvider) Load(context.Context) (map[string]any, error) {
+	if p.Decoder == nil {
+		return nil, errors.New("no decoder set")
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSHA256(data, p.SHA256); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.Path, err)
+	}
+	files, err := readArchive(p.Path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest []string
+	for name := range files {
+		if !strings.Contains(name, "/") && strings.TrimSuffix(name, path.Ext(name)) == "manifest" {
+			manifest = append(manifest, name)
+		}
+	}
+	if len(manifest) != 1 {
+		return nil, fmt.Errorf("expected one manifest in archive, found %d", len(manifest))
+	}
+	values, err := p.Decoder.Decode(files[manifest[0]], path.Ext(manifest[0]))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", manifest[0], err)
+	}
+	list, ok := values["files"].([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: missing files list", manifest[0])
+	}
+
+	merged := make(map[string]any)
+	for _, item := range list {
+		name := path.Clean(stringify(item))
+		content, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: file %s not found in archive", manifest[0], name)
+		}
+		values, err := p.Decoder.Decode(content, path.Ext(name))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		mergeMaps(merged, values)
+	}
+	return merged, nil
+}
+
+// readArchive returns the regular files of a zip, tar or gzipped tar archive
+// by cleaned path, choosing the format from the name.
+func readArchive(name string, data []byte) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			files[path.Clean(f.Name)] = content
+		}
+		return files, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return readTar(gz, files)
+	case strings.HasSuffix(lower, ".tar"):
+		return readTar(bytes.NewReader(data), files)
+	}
+	return nil, fmt.Errorf("unsupported archive %s", path.Base(name))
+}
+
+func readTar(r io.Reader, files map[string][]byte) (map[string][]byte, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(hdr.Name)] = content
+	}
+}
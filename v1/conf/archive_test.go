@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var bundleFiles = map[string]string{
+	"manifest.yaml":         "files:\n  - base.yaml\n  - overlays/prod.json\n",
+	"base.yaml":             "server:\n  port: 8080\n  host: localhost\nlog: info\n",
+	"overlays/prod.json":    `{"server": {"host": "prod.local"}}`,
+	"overlays/ignored.yaml": "log: debug\n",
+}
+
+func writeZipBundle(t *testing.T, path string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range bundleFiles {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTarGzBundle(t *testing.T, path string) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range bundleFiles {
+		hdr := &tar.Header{Name: "./" + name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchiveProvider(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "bundle.zip")
+	tgzPath := filepath.Join(dir, "bundle.tar.gz")
+	writeZipBundle(t, zipPath)
+	writeTarGzBundle(t, tgzPath)
+
+	for _, path := range []string{zipPath, tgzPath} {
+		c := New()
+		c.AddProvider(NewArchiveProvider(path, c))
+		if err := c.ReadProviders(context.Background()); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if got := c.GetString("server.host"); got != "prod.local" {
+			t.Fatalf("%s: expected overlay to override base, got %q", path, got)
+		}
+		if got := c.GetInt("server.port"); got != 8080 {
+			t.Fatalf("%s: expected base value, got %d", path, got)
+		}
+		if got := c.GetString("log"); got != "info" {
+			t.Fatalf("%s: expected files outside the manifest to be ignored, got %q", path, got)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeFormat names backups so that they sort chronologically.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// SetBackupCount makes WriteConfig and WriteConfigAs copy the file they are
+// about to overwrite to "<file>.<timestamp>.bak" first, keeping the n most
+// recent copies and removing older ones. Zero, the default, disables backups.
+func (c *Config) SetBackupCount(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.backups = n
+}
+
+// Backups returns the backups of path written by WriteConfig, oldest first.
+func Backups(path string) ([]string, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, base+".") || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ".bak")
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		out = append(out, filepath.Join(dir, name))
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// backupLocked copies path to a new timestamped backup and prunes the oldest
+// ones beyond c.backups. A missing path is not an error.
+func (c *Config) backupLocked(path string) error {
+	if c.backups == 0 {
+		return nil
+	}
+	data, err := c.readFileLocked(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	name := path + "." + time.Now().UTC().Format(backupTimeFormat) + ".bak"
+	if err := os.WriteFile(name, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	existing, err := Backups(path)
+	if err != nil {
+		return err
+	}
+	for len(existing) > c.backups {
+		if err := os.Remove(existing[0]); err != nil {
+			return err
+		}
+		existing = existing[1:]
+	}
+	return nil
+}
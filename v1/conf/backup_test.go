@@ -0,0 +1,42 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteConfigBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	c := New()
+	c.SetConfigFile(path)
+	c.SetBackupCount(2)
+
+	for port := 1; port <= 4; port++ {
+		c.MergeConfigMap(map[string]any{"port": port})
+		if err := c.WriteConfig(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backups, err := Backups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %v", backups)
+	}
+	// The newest backup holds the file as it was before the last write.
+	f, err := os.Open(backups[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	restored := New()
+	restored.SetConfigType("yaml")
+	if err := restored.ReadConfig(f); err != nil {
+		t.Fatal(err)
+	}
+	if restored.GetInt("port") != 3 {
+		t.Fatalf("expected backup of port 3, got %d", restored.GetInt("port"))
+	}
+}
@@ -0,0 +1,224 @@
+package conf
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetBigInt returns the value for the key as an arbitrary precision integer,
+// or nil when the key is missing or does not hold an integer. Integers too
+// large for int64, such as 123456789012345678901234, are returned exactly.
+func (c *Config) GetBigInt(key string) *big.Int {
+	i, err := c.GetBigIntE(key)
+	c.noteCoercion(err)
+	return i
+}
+
+// GetBigIntE is like GetBigInt but reports missing keys and values that are
+// not integers.
+func (c *Config) GetBigIntE(key string) (*big.Int, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	i, ok := toBigInt(v)
+	if !ok {
+		return nil, &ConversionError{Key: key, Value: v, Target: "big.Int"}
+	}
+	return i, nil
+}
+
+// GetDecimal returns the value for the key as an exact decimal, or nil when
+// the key is missing or does not hold a number. Decimal literals keep every
+// digit, so "19.99" is exactly 1999/100 rather than the nearest float64.
+func (c *Config) GetDecimal(key string) *big.Rat {
+	r, err := c.GetDecimalE(key)
+	c.noteCoercion(err)
+	return r
+}
+
+// GetDecimalE is like GetDecimal but reports missing keys and values that
+// are not numbers.
+func (c *Config) GetDecimalE(key string) (*big.Rat, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	r, ok := toDecimal(v)
+	if !ok {
+		return nil, &ConversionError{Key: key, Value: v, Target: "decimal"}
+	}
+	return r, nil
+}
+
+func toBigInt(v any) (*big.Int, bool) {
+	switch val := v.(type) {
+	case int:
+		return big.NewInt(int64(val)), true
+	case int64:
+		return big.NewInt(val), true
+	case uint64:
+		return new(big.Int).SetUint64(val), true
+	case float64:
+		if math.IsInf(val, 0) || val != math.Trunc(val) {
+			return nil, false
+		}
+		i, _ := big.NewFloat(val).Int(nil)
+		return i, true
+	case json.Number:
+		return toBigInt(string(val))
+	case string:
+		r, ok := new(big.Rat).SetString(strings.TrimSpace(val))
+		if !ok || !r.IsInt() {
+			return nil, false
+		}
+		return new(big.Int).Set(r.Num()), true
+	}
+	return nil, false
+}
+
+func toDecimal(v any) (*big.Rat, bool) {
+	switch val := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(val)), true
+	case int64:
+		return new(big.Rat).SetInt64(val), true
+	case uint64:
+		return new(big.Rat).SetUint64(val), true
+	case float64:
+		// The shortest representation is the decimal the float was parsed
+		// from, so 0.1 yields 1/10 rather than its binary approximation.
+		return toDecimal(strconv.FormatFloat(val, 'g', -1, 64))
+	case json.Number:
+		return toDecimal(string(val))
+	case string:
+		return new(big.Rat).SetString(strings.TrimSpace(val))
+	}
+	return nil, false
+}
+
+// exactFloat reports whether f, parsed from the decimal literal text, holds
+// the value of text without rounding. Literals that are not plain decimals
+// are considered exact.
+func exactFloat(text string, f float64) bool {
+	want, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return true
+	}
+	got, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return ok && want.Cmp(got) == 0
+}
+
+// preciseNumbers replaces, in a value decoded with json.Decoder.UseNumber,
+// the numbers float64 represents exactly with float64 and keeps the others as
+// json.Number, so large integers and long decimals are not rounded.
+func preciseNumbers(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			val[k] = preciseNumbers(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = preciseNumbers(item)
+		}
+	case json.Number:
+		if f, err := val.Float64(); err == nil && exactFloat(string(val), f) {
+			return f
+		}
+	}
+	return v
+}
+
+// preciseMarker prefixes the scalars retagged by preciseYAMLNumbers so that
+// yamlNumbers can tell them from the strings of the document.
+const preciseMarker = "\x00conf:number:"
+
+// preciseYAMLNumbers retags the float scalars below node that float64 would
+// round, so they decode as marked strings holding every digit.
+func preciseYAMLNumbers(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!float" {
+		var f float64
+		if err := node.Decode(&f); err == nil && !exactFloat(node.Value, f) {
+			node.Tag = "!!str"
+			node.Value = preciseMarker + strings.ReplaceAll(strings.TrimPrefix(node.Value, "+"), "_", "")
+		}
+	}
+	for _, child := range node.Content {
+		preciseYAMLNumbers(child)
+	}
+}
+
+// yamlNumbers replaces the strings marked by preciseYAMLNumbers with
+// json.Number, the representation JSONLoader uses for the same values.
+func yamlNumbers(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			val[k] = yamlNumbers(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = yamlNumbers(item)
+		}
+	case string:
+		if text, ok := strings.CutPrefix(val, preciseMarker); ok {
+			return json.Number(text)
+		}
+	}
+	return v
+}
+
+// isIntegerNumber reports whether n is written as an integer.
+func isIntegerNumber(n json.Number) bool {
+	return !strings.ContainsAny(string(n), ".eE")
+}
+
+// numberLiterals returns a copy of v in which every json.Number is replaced
+// by literal(n), for the encoders that would otherwise write it as a string
+// or round it to a float64.
+func numberLiterals(v any, literal func(json.Number) any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = numberLiterals(item, literal)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = numberLiterals(item, literal)
+		}
+		return out
+	case json.Number:
+		return literal(val)
+	}
+	return v
+}
+
+// yamlNumber writes n as a plain YAML scalar.
+func yamlNumber(n json.Number) any {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: string(n)}
+}
+
+// tomlNumber writes a json.Number as a TOML number. TOML integers are limited
+// to 64 bits, so larger ones are written as strings, which GetBigInt reads
+// back without loss.
+type tomlNumber json.Number
+
+func (n tomlNumber) MarshalTOML() ([]byte, error) {
+	if _, err := json.Number(n).Int64(); err != nil && isIntegerNumber(json.Number(n)) {
+		return []byte(strconv.Quote(string(n))), nil
+	}
+	return []byte(n), nil
+}
+
+func newTOMLNumber(n json.Number) any {
+	return tomlNumber(n)
+}
@@ -0,0 +1,107 @@
+package conf
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestGetBigIntAndDecimal(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		data := `{"supply": 123456789012345678901234, "price": 12345678901234567.89, "fee": 0.1, "count": 42}`
+		c := New()
+		c.SetConfigType(format)
+		if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		want, _ := new(big.Int).SetString("123456789012345678901234", 10)
+		if got := c.GetBigInt("supply"); got == nil || got.Cmp(want) != 0 {
+			t.Fatalf("%s: expected supply %s, got %v", format, want, got)
+		}
+		price, _ := new(big.Rat).SetString("12345678901234567.89")
+		if got := c.GetDecimal("price"); got == nil || got.Cmp(price) != 0 {
+			t.Fatalf("%s: expected price %s, got %v", format, price.FloatString(2), got)
+		}
+		if got := c.GetDecimal("fee"); got == nil || got.Cmp(big.NewRat(1, 10)) != 0 {
+			t.Fatalf("%s: expected fee 1/10, got %v", format, got)
+		}
+		if got := c.GetInt("count"); got != 42 {
+			t.Fatalf("%s: expected count 42, got %d", format, got)
+		}
+		if _, err := c.GetBigIntE("price"); err == nil {
+			t.Fatalf("%s: expected an error converting a decimal to big.Int", format)
+		}
+	}
+}
+
+func TestPreciseNumbersRoundTrip(t *testing.T) {
+	data := `{"supply": 123456789012345678901234, "price": 12345678901234567.89, "max": 18446744073709551615}`
+	for _, format := range []string{"json", "yaml", "toml"} {
+		c := New()
+		c.SetConfigType("json")
+		if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+		out, err := c.MarshalTo(format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if format != "toml" && strings.Contains(string(out), `"1`) {
+			t.Fatalf("%s: expected numeric literals, got\n%s", format, out)
+		}
+		if !strings.Contains(string(out), "12345678901234567.89") {
+			t.Fatalf("%s: expected the price literal, got\n%s", format, out)
+		}
+
+		back := New()
+		back.SetConfigType(format)
+		if err := back.ReadConfig(strings.NewReader(string(out))); err != nil {
+			t.Fatalf("%s: %v\n%s", format, err, out)
+		}
+		want, _ := new(big.Int).SetString("123456789012345678901234", 10)
+		if got := back.GetBigInt("supply"); got == nil || got.Cmp(want) != 0 {
+			t.Fatalf("%s: expected supply %s after a round trip, got %v", format, want, got)
+		}
+		if format == "toml" {
+			// TOMLLoader reads floats as float64.
+			continue
+		}
+		price, _ := new(big.Rat).SetString("12345678901234567.89")
+		if got := back.GetDecimal("price"); got == nil || got.Cmp(price) != 0 {
+			t.Fatalf("%s: expected price %s after a round trip, got %v", format, price.FloatString(2), got)
+		}
+	}
+}
+
+func TestPreciseNumbersConvert(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		c := New()
+		c.SetConfigType(format)
+		data := `{"max": 18446744073709551615, "price": 12345678901234567.89}`
+		if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.GetFloat64("max"); got != 18446744073709551615 {
+			t.Fatalf("%s: expected max as a float, got %v", format, got)
+		}
+		if _, err := c.GetIntE("max"); err == nil {
+			t.Fatalf("%s: expected an error reading max as an int", format)
+		}
+		if got := c.GetInt("price"); got != 12345678901234568 {
+			t.Fatalf("%s: expected price truncated like a float, got %d", format, got)
+		}
+		if got := c.GetFloat64("price"); got != 12345678901234567.89 {
+			t.Fatalf("%s: expected price as a float, got %v", format, got)
+		}
+		v, _ := c.Snapshot().Get("price")
+		if got := typeOf(v); got != TypeFloat {
+			t.Fatalf("%s: expected price to be typed as a float, got %v", format, got)
+		}
+		c.Declare("max", Meta{Type: TypeFloat})
+		c.Declare("price", Meta{Type: TypeInt})
+		if err := c.Validate(); err != nil {
+			t.Fatalf("%s: expected the precise numbers to validate, got %v", format, err)
+		}
+	}
+}
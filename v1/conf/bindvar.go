@@ -0,0 +1,128 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+type varBinding struct {
+	key    string
+	target any
+}
+
+// BindVar keeps *target in sync with key: it is set immediately, when key
+// is defined, and again after every applied change below key, whether it
+// comes from a reload, a provider or a runtime merge. Long-lived components
+// can then hold on to the variable instead of calling a getter.
+//
+// Targets of type *atomic.Int32, *atomic.Int64, *atomic.Uint32,
+// *atomic.Uint64 and *atomic.Bool are converted like GetInt and GetBool,
+// and together with *atomic.Value they are updated with Store and can be
+// read concurrently. Any other pointer, to a scalar, slice or struct, is
+// decoded like Unmarshal and written while holding the lock returned by
+// VarLock, which readers hold too:
+//
+//	var maxConns int
+//	cfg.BindVar(&maxConns, "pool.max")
+//	...
+//	l := cfg.VarLock()
+//	l.Lock()
+//	n := maxConns
+//	l.Unlock()
+func (c *Config) BindVar(target any, key string) error {
+	if target == nil || reflect.TypeOf(target).Kind() != reflect.Pointer || reflect.ValueOf(target).IsNil() {
+		return errors.New("conf: BindVar target must be a non-nil pointer")
+	}
+	b := varBinding{key: key, target: target}
+	if err := c.syncVar(b); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vars = append(c.vars, b)
+	return nil
+}
+
+// VarLock returns the lock to hold while reading variables bound with
+// BindVar that are not atomic types.
+func (c *Config) VarLock() sync.Locker {
+	return c.varsMu.RLocker()
+}
+
+// syncVars updates the bound variables affected by events. It must be called
+// without holding c.mu.
+func (c *Config) syncVars(events []ChangeEvent) {
+	c.mu.RLock()
+	vars := append([]varBinding(nil), c.vars...)
+	c.mu.RUnlock()
+	for _, b := range vars {
+		match := KeyPrefix(b.key)
+		for _, ev := range events {
+			if !match(ev) {
+				continue
+			}
+			if err := c.syncVar(b); err != nil {
+				c.log().Error("conf: failed to update bound variable", "key", b.key, "error", err)
+				c.reportError(err)
+			}
+			break
+		}
+	}
+}
+
+// syncVar stores the current value of b.key into b.target. An undefined key
+// leaves the target unchanged.
+func (c *Config) syncVar(b varBinding) error {
+	v, ok := c.load().read(b.key)
+	if !ok {
+		return nil
+	}
+	var err error
+	switch t := b.target.(type) {
+	case *atomic.Int32:
+		var n int
+		if n, err = c.GetIntE(b.key); err == nil {
+			t.Store(int32(n))
+		}
+	case *atomic.Int64:
+		var n int
+		if n, err = c.GetIntE(b.key); err == nil {
+			t.Store(int64(n))
+		}
+	case *atomic.Uint32:
+		var n int
+		if n, err = c.GetIntE(b.key); err == nil {
+			t.Store(uint32(n))
+		}
+	case *atomic.Uint64:
+		var n int
+		if n, err = c.GetIntE(b.key); err == nil {
+			t.Store(uint64(n))
+		}
+	case *atomic.Bool:
+		var flag bool
+		if flag, err = c.GetBoolE(b.key); err == nil {
+			t.Store(flag)
+		}
+	case *atomic.Value:
+		if old := t.Load(); v == nil || (old != nil && reflect.TypeOf(old) != reflect.TypeOf(v)) {
+			err = fmt.Errorf("cannot store %T in atomic.Value", v)
+		} else {
+			t.Store(cloneValue(v))
+		}
+	default:
+		fresh := reflect.New(reflect.TypeOf(b.target).Elem())
+		if err = decode(cloneValue(v), fresh.Interface()); err == nil {
+			c.varsMu.Lock()
+			reflect.ValueOf(b.target).Elem().Set(fresh.Elem())
+			c.varsMu.Unlock()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("conf: binding %q: %w", b.key, err)
+	}
+	return nil
+}
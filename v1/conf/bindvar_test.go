@@ -0,0 +1,46 @@
+package conf
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestBindVar(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"pool": map[string]any{"max": 10, "name": "main"}, "debug": "yes"})
+
+	type pool struct {
+		Max  int    `mapstructure:"max"`
+		Name string `mapstructure:"name"`
+	}
+	var (
+		maxConns int
+		debug    atomic.Bool
+		settings pool
+	)
+	if err := c.BindVar(&maxConns, "pool.max"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindVar(&debug, "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindVar(&settings, "pool"); err != nil {
+		t.Fatal(err)
+	}
+	if maxConns != 10 || !debug.Load() || settings.Name != "main" {
+		t.Fatalf("expected initial values, got %d %v %+v", maxConns, debug.Load(), settings)
+	}
+
+	c.MergeConfigMap(map[string]any{"pool": map[string]any{"max": "25"}, "debug": false})
+	l := c.VarLock()
+	l.Lock()
+	got, gotPool := maxConns, settings
+	l.Unlock()
+	if got != 25 || debug.Load() || gotPool.Max != 25 || gotPool.Name != "main" {
+		t.Fatalf("expected bound variables to follow the change, got %d %v %+v", got, debug.Load(), gotPool)
+	}
+
+	if err := c.BindVar(maxConns, "pool.max"); err == nil {
+		t.Fatalf("expected error for a non-pointer target")
+	}
+}
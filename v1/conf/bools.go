@@ -0,0 +1,81 @@
+package conf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// boolWords holds the lower case spellings accepted as true and as false
+// when set with SetBoolWords.
+type boolWords struct {
+	trues, falses map[string]bool
+}
+
+// SetBoolWords replaces the spellings the boolean getters accept in string
+// values, matched case-insensitively, e.g. SetBoolWords([]string{"true",
+// "enabled"}, []string{"false", "disabled"}). Under CoercionStrict the
+// listed words are accepted too. Calling it with no words restores the
+// default spellings; a word listed as both true and false returns an error.
+func (c *Config) SetBoolWords(trueWords, falseWords []string) error {
+	var words *boolWords
+	if len(trueWords) > 0 || len(falseWords) > 0 {
+		words = &boolWords{trues: make(map[string]bool), falses: make(map[string]bool)}
+		for _, w := range trueWords {
+			words.trues[strings.ToLower(strings.TrimSpace(w))] = true
+		}
+		for _, w := range falseWords {
+			w = strings.ToLower(strings.TrimSpace(w))
+			if words.trues[w] {
+				return fmt.Errorf("conf: boolean word %q listed as both true and false", w)
+			}
+			words.falses[w] = true
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
+	}
+	c.bools = words
+	c.publishLocked()
+	return nil
+}
+
+// parseBool accepts the words set with SetBoolWords or, by default, extends
+// strconv.ParseBool with the yes/no, on/off and y/n spellings common in INI
+// and YAML files, case-insensitively.
+func (o parseOptions) parseBool(s string) (bool, bool) {
+	word := strings.ToLower(strings.TrimSpace(s))
+	if o.bools != nil {
+		switch {
+		case o.bools.trues[word]:
+			return true, true
+		case o.bools.falses[word]:
+			return false, true
+		}
+		return false, false
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b, true
+	}
+	switch word {
+	case "yes", "y", "on":
+		return true, true
+	case "no", "n", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// strictBool reports whether s is a boolean spelling accepted under
+// CoercionStrict: one set with SetBoolWords, or by default one that
+// strconv.ParseBool understands.
+func (o parseOptions) strictBool(s string) bool {
+	if o.bools != nil {
+		_, ok := o.parseBool(s)
+		return ok
+	}
+	_, err := strconv.ParseBool(s)
+	return err == nil
+}
@@ -0,0 +1,130 @@
+package conf
+
+import (
+	"sync"
+	"time"
+)
+
+// Topic identifies a kind of event published on the EventBus.
+type Topic int
+
+const (
+	// TopicReload is published after every successful reload of the config
+	// file.
+	TopicReload Topic = iota
+	// TopicChange is published once per changed key, with Event.Change set.
+	TopicChange
+	// TopicError carries, in Event.Err, the errors reported to the OnError
+	// hooks.
+	TopicError
+)
+
+func (t Topic) String() string {
+	switch t {
+	case TopicReload:
+		return "reload"
+	case TopicChange:
+		return "change"
+	case TopicError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a notification published on the EventBus.
+type Event struct {
+	Topic  Topic
+	Time   time.Time
+	Change ChangeEvent
+	Err    error
+}
+
+// eventBuffer is the capacity of the channels returned by Subscribe.
+const eventBuffer = 64
+
+// EventBus delivers the reload, change and error notifications of a Config.
+// OnError, Subscribe and OnChangeUnmarshal are built on it.
+type EventBus struct {
+	mu   sync.Mutex
+	subs map[Topic][]*busSub
+}
+
+type busSub struct {
+	handler func(Event)
+}
+
+// Handle registers fn to be called synchronously, in registration order, for
+// every event published on topic. The returned function cancels it.
+func (b *EventBus) Handle(topic Topic, fn func(Event)) func() {
+	sub := &busSub{handler: fn}
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[Topic][]*busSub)
+	}
+	b.subs[topic] = append(b.subs[topic], sub)
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		subs := b.subs[topic]
+		for i, s := range subs {
+			if s == sub {
+				b.subs[topic] = append(subs[:i:i], subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel receiving the events published on topic and
+// the function that cancels the subscription and closes the channel. The
+// channel is buffered; events published while it is full are dropped rather
+// than blocking the publisher.
+func (b *EventBus) Subscribe(topic Topic) (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+	cancel := b.Handle(topic, func(ev Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	})
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			cancel()
+			mu.Lock()
+			closed = true
+			close(ch)
+			mu.Unlock()
+		})
+	}
+}
+
+// Publish delivers ev to the handlers of its topic, setting its time when
+// unset.
+func (b *EventBus) Publish(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	b.mu.Lock()
+	subs := append([]*busSub(nil), b.subs[ev.Topic]...)
+	b.mu.Unlock()
+	for _, sub := range subs {
+		sub.handler(ev)
+	}
+}
+
+// Events returns the bus carrying the notifications of c.
+func (c *Config) Events() *EventBus {
+	return c.events
+}
@@ -0,0 +1,40 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEventBus(t *testing.T) {
+	c := New()
+	changes, cancel := c.Events().Subscribe(TopicChange)
+	errs, cancelErrs := c.Events().Subscribe(TopicError)
+	defer cancelErrs()
+
+	c.MergeConfigMap(map[string]any{"port": 80})
+	ev := <-changes
+	if ev.Topic != TopicChange || ev.Change.Key != "port" || ev.Change.New != 80 {
+		t.Fatalf("unexpected change event %+v", ev)
+	}
+
+	boom := errors.New("boom")
+	c.reportError(boom)
+	if ev := <-errs; !errors.Is(ev.Err, boom) {
+		t.Fatalf("expected error event, got %+v", ev)
+	}
+
+	cancel()
+	c.MergeConfigMap(map[string]any{"port": 81})
+	if _, ok := <-changes; ok {
+		t.Fatalf("expected channel to be closed after cancel")
+	}
+	cancel()
+
+	var order []string
+	c.Events().Handle(TopicChange, func(Event) { order = append(order, "first") })
+	c.Events().Handle(TopicChange, func(Event) { order = append(order, "second") })
+	c.MergeConfigMap(map[string]any{"port": 82})
+	if len(order) != 2 || order[0] != "first" {
+		t.Fatalf("expected handlers in registration order, got %v", order)
+	}
+}
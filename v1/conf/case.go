@@ -0,0 +1,70 @@
+package conf
+
+import "strings"
+
+// SetKeysCaseInsensitive makes keys case-insensitive, as Viper does, so
+// "Server.Port" and "server.port" resolve identically whether the key comes
+// from a TOML, YAML or JSON file, a provider, a default or a getter. Keys are
+// stored lower-cased: AllKeys, AllSettings and Unmarshal report them that
+// way, and when two spellings of a key are loaded the lower-case one wins.
+// Key patterns given to LockKey, MarkSecret and similar are matched against
+// the lower-cased keys. Disabling it keeps the keys already lower-cased.
+func (c *Config) SetKeysCaseInsensitive(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.foldKeys = enabled
+	c.publishLocked()
+}
+
+// foldLayersLocked lower-cases the keys of every layer and of the
+// declarations keyed by configuration key. The caller must hold c.mu.
+func (c *Config) foldLayersLocked() {
+	c.defaults = foldKeys(c.defaults)
+	c.values = foldKeys(c.values)
+	c.overrides = foldKeys(c.overrides)
+	c.providerValues = foldKeys(c.providerValues)
+	c.raisedValues = foldKeys(c.raisedValues)
+	c.envBindings = foldKeyMap(c.envBindings)
+	c.defaultFuncs = foldKeyMap(c.defaultFuncs)
+}
+
+// foldKeys returns a copy of m with every key, at every level, lower-cased.
+// Keys differing only by case are merged, the lower-case spelling applied
+// last.
+func foldKeys(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for _, k := range sortedKeys(m) {
+		mergeMaps(out, map[string]any{strings.ToLower(k): foldValue(m[k])})
+	}
+	return out
+}
+
+func foldValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return foldKeys(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = foldValue(item)
+		}
+		return out
+	}
+	return cloneValue(v)
+}
+
+// foldKeyMap returns a copy of m with its keys lower-cased.
+func foldKeyMap[V any](m map[string]V) map[string]V {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]V, len(m))
+	for k, v := range m {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetKeysCaseInsensitive(t *testing.T) {
+	t.Setenv("APP_SERVER_HOST", "env.example.com")
+	c := New()
+	c.SetKeysCaseInsensitive(true)
+	c.SetEnvPrefix("APP")
+	c.SetDefault("Server.Timeout", "5s")
+	c.SetConfigType("toml")
+	if err := c.ReadConfig(strings.NewReader("[Server]\nPort = 8080\nHost = \"file\"\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"server.port", "Server.Port", "SERVER.PORT"} {
+		if got := c.GetInt(key); got != 8080 {
+			t.Fatalf("expected %s to be 8080, got %d", key, got)
+		}
+	}
+	if got := c.GetDuration("server.timeout").String(); got != "5s" {
+		t.Fatalf("expected default timeout 5s, got %s", got)
+	}
+	c.Set("SERVER.port", 9090)
+	if got := c.GetInt("Server.Port"); got != 9090 {
+		t.Fatalf("expected override 9090, got %d", got)
+	}
+	c.AutomaticEnv()
+	if got := c.GetString("server.HOST"); got != "env.example.com" {
+		t.Fatalf("expected env host, got %q", got)
+	}
+
+	want := []string{"server.host", "server.port", "server.timeout"}
+	if got := c.AllKeys(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected keys %v, got %v", want, got)
+	}
+	var out struct {
+		Server struct {
+			Port int `mapstructure:"port"`
+		} `mapstructure:"server"`
+	}
+	if err := c.Unmarshal("", &out); err != nil || out.Server.Port != 9090 {
+		t.Fatalf("expected unmarshaled port 9090, got %d, %v", out.Server.Port, err)
+	}
+}
+
+func TestKeysCaseSensitiveByDefault(t *testing.T) {
+	c := New()
+	c.SetDefault("Server.Port", 8080)
+	if c.IsSet("server.port") {
+		t.Fatalf("expected keys to be case-sensitive by default")
+	}
+}
+
+func TestSetKeysCaseInsensitiveCloneAndSub(t *testing.T) {
+	c := New()
+	c.SetKeysCaseInsensitive(true)
+	c.MergeConfigMap(map[string]any{"Server": map[string]any{"Port": 8080}})
+
+	clone := c.Clone()
+	clone.MergeConfigMap(map[string]any{"SERVER": map[string]any{"HOST": "a"}})
+	if got := clone.GetInt("Server.Port"); got != 8080 {
+		t.Fatalf("expected the clone to fold keys, got %d", got)
+	}
+	if got := clone.GetString("server.host"); got != "a" {
+		t.Fatalf("expected the clone to fold merged keys, got %q", got)
+	}
+
+	sub := c.Sub("SERVER")
+	if sub == nil {
+		t.Fatalf("expected a sub config")
+	}
+	if got := sub.GetInt("PORT"); got != 8080 {
+		t.Fatalf("expected the sub config to fold keys, got %d", got)
+	}
+}
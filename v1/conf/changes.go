@@ -0,0 +1,135 @@
+package conf
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ChangeType tells whether a key was added, updated or removed.
+type ChangeType int
+
+const (
+	ChangeAdded ChangeType = iota
+	ChangeUpdated
+	ChangeRemoved
+)
+
+// Change sources reported in ChangeEvent.Source.
+const (
+	// ChangeSourceFile marks changes applied by a reload of the config file.
+	ChangeSourceFile = "file"
+	// ChangeSourceRuntime marks changes applied programmatically, such as
+	// by MergeConfigMap.
+	ChangeSourceRuntime = "runtime"
+)
+
+// ChangeEvent describes the change of a single effective key.
+type ChangeEvent struct {
+	Key    string
+	Type   ChangeType
+	Old    any
+	New    any
+	Source string
+	Time   time.Time
+
+	// sep is the key delimiter of the Config the event comes from.
+	sep string
+}
+
+// Subscribe registers handler for the change events accepted by match, or for
+// every event when match is nil. Events are delivered after a change has been
+// applied, one call per changed key. The returned function cancels the
+// subscription.
+func (c *Config) Subscribe(match func(ChangeEvent) bool, handler func(ChangeEvent)) func() {
+	return c.events.Handle(TopicChange, func(ev Event) {
+		if match == nil || match(ev.Change) {
+			handler(ev.Change)
+		}
+	})
+}
+
+// KeyPrefix returns a predicate matching events for prefix itself and every
+// key below it, following the key delimiter of the Config.
+func KeyPrefix(prefix string) func(ChangeEvent) bool {
+	return func(ev ChangeEvent) bool {
+		sep := ev.sep
+		if sep == "" {
+			sep = defaultKeyDelimiter
+		}
+		return ev.Key == prefix || strings.HasPrefix(ev.Key, prefix+sep)
+	}
+}
+
+// update runs fn holding c.commitMu and c.mu for writing and, unless it
+// fails, dispatches the changes it made to the effective configuration with
+// the given source.
+func (c *Config) update(source string, fn func() error) error {
+	c.commitMu.Lock()
+	c.mu.Lock()
+	before := c.load()
+	err := fn()
+	after := c.load()
+	c.mu.Unlock()
+	c.commitMu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.dispatchChanges(diffStates(before, after, source))
+	return nil
+}
+
+// dispatchChanges records events in the history and publishes them on the
+// event bus. It must be called without holding c.mu.
+func (c *Config) dispatchChanges(events []ChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+	c.mu.Lock()
+	for _, ev := range events {
+		c.history.add(ev)
+	}
+	c.mu.Unlock()
+	c.syncVars(events)
+	c.runHooks(func(h Hooks) { h.OnChangeApplied(events) })
+	for _, ev := range events {
+		c.events.Publish(Event{Topic: TopicChange, Time: ev.Time, Change: ev})
+	}
+}
+
+// diffStates returns one event per effective key that differs between before
+// and after, in key order.
+func diffStates(before, after *state, source string) []ChangeEvent {
+	old := before.effective()
+	cur := after.effective()
+	keys := make([]string, 0, len(old)+len(cur))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range cur {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	now := time.Now()
+	var events []ChangeEvent
+	for _, key := range keys {
+		ov, hadOld := old[key]
+		nv, hasNew := cur[key]
+		ev := ChangeEvent{Key: key, Old: ov, New: nv, Source: source, Time: now, sep: after.delim}
+		switch {
+		case !hadOld:
+			ev.Type = ChangeAdded
+		case !hasNew:
+			ev.Type = ChangeRemoved
+		case !reflect.DeepEqual(ov, nv):
+			ev.Type = ChangeUpdated
+		default:
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events
+}
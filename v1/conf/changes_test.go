@@ -0,0 +1,98 @@
+package conf
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSubscribeWithPredicate(t *testing.T) {
+	tmp, err := os.CreateTemp("", "cfg*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := os.WriteFile(tmp.Name(), []byte("server:\n  port: 1\n  host: a\nlog: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(tmp.Name())
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(chan ChangeEvent, 10)
+	c.Subscribe(KeyPrefix("server"), func(ev ChangeEvent) {
+		events <- ev
+	})
+	var all []ChangeEvent
+	cancel := c.Subscribe(nil, func(ev ChangeEvent) {
+		all = append(all, ev)
+	})
+	cancel()
+
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := os.WriteFile(tmp.Name(), []byte("server:\n  port: 2\n  tls: true\nlog: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []ChangeEvent
+	timeout := time.After(2 * time.Second)
+	for len(got) < 3 {
+		select {
+		case ev := <-events:
+			got = append(got, ev)
+		case <-timeout:
+			t.Fatalf("expected three server events, got %+v", got)
+		}
+	}
+	want := []struct {
+		key string
+		typ ChangeType
+	}{
+		{"server.host", ChangeRemoved},
+		{"server.port", ChangeUpdated},
+		{"server.tls", ChangeAdded},
+	}
+	for i, w := range want {
+		if got[i].Key != w.key || got[i].Type != w.typ || got[i].Source != ChangeSourceFile {
+			t.Fatalf("unexpected event %d: %+v", i, got[i])
+		}
+	}
+	if got[1].Old != 1 || got[1].New != 2 {
+		t.Fatalf("expected port to change from 1 to 2, got %+v", got[1])
+	}
+	if len(all) != 0 {
+		t.Fatalf("expected cancelled subscription to receive nothing, got %+v", all)
+	}
+}
+
+func TestRuntimeChangeEvents(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"server": map[string]any{"port": 1}})
+
+	var events []ChangeEvent
+	c.Subscribe(nil, func(ev ChangeEvent) { events = append(events, ev) })
+	c.MergeConfigMap(map[string]any{"server": map[string]any{"port": 2, "host": "a"}})
+	c.MergeConfigMap(map[string]any{"server": map[string]any{"port": 2}})
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	for _, ev := range events {
+		if ev.Source != ChangeSourceRuntime {
+			t.Fatalf("expected runtime source, got %+v", ev)
+		}
+	}
+	if events[0].Key != "server.host" || events[0].Type != ChangeAdded {
+		t.Fatalf("unexpected first event %+v", events[0])
+	}
+	if events[1].Key != "server.port" || events[1].Old != 1 || events[1].New != 2 {
+		t.Fatalf("unexpected second event %+v", events[1])
+	}
+}
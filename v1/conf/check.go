@@ -0,0 +1,40 @@
+package conf
+
+// Report describes what reading the config file again would do.
+type Report struct {
+	// File is the config file that would be read, empty when none is set.
+	File string
+	// Changes lists the effective keys that would change.
+	Changes []ChangeEvent
+}
+
+// CheckInConfig runs the ReadInConfig pipeline - locating, reading and
+// decoding the file, then running the validators and Validate against the
+// result - without touching the live configuration. It answers whether the
+// next reload would succeed and what it would change.
+func (c *Config) CheckInConfig() (Report, error) {
+	var report Report
+	c.mu.RLock()
+	file, err := c.findConfigFileLocked()
+	var parsed map[string]any
+	if err == nil && file != "" {
+		report.File = file
+		parsed, err = c.readConfigFileLocked(file)
+	}
+	if err != nil || file == "" {
+		c.mu.RUnlock()
+		return report, err
+	}
+	// The candidate is resolved without the lock, as computed defaults and
+	// validators may call back into c.
+	candidate := c.stateLocked(parsed)
+	validators := append([]func(Snapshot) error(nil), c.validators...)
+	rules := c.rulesLocked()
+	c.mu.RUnlock()
+
+	if err := c.checkCandidate(validators, candidate); err != nil {
+		return report, err
+	}
+	report.Changes = diffStates(c.load(), candidate, ChangeSourceFile)
+	return report, rules.validate(candidate)
+}
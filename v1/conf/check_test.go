@@ -0,0 +1,57 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestCheckInConfig(t *testing.T) {
+	tmp, err := os.CreateTemp("", "cfg*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := os.WriteFile(tmp.Name(), []byte("port: 1\ndsn: db\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.Require("dsn")
+	c.SetConfigFile(tmp.Name())
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(tmp.Name(), []byte("port: 2\ndsn: db\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	report, err := c.CheckInConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.File != tmp.Name() || len(report.Changes) != 1 || report.Changes[0].Key != "port" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if got := c.GetInt("port"); got != 1 {
+		t.Fatalf("expected dry run not to apply changes, got port %d", got)
+	}
+
+	if err := os.WriteFile(tmp.Name(), []byte("port: 3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var verr *ValidationError
+	if _, err := c.CheckInConfig(); !errors.As(err, &verr) {
+		t.Fatalf("expected validation error for missing dsn, got %v", err)
+	}
+
+	if err := os.WriteFile(tmp.Name(), []byte("::invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CheckInConfig(); err == nil {
+		t.Fatalf("expected parse error")
+	}
+	if got := c.GetString("dsn"); got != "db" {
+		t.Fatalf("expected live config to be untouched, got dsn %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+package conf
+
+import "maps"
+
+// Clone returns an independent copy of c: its defaults, values, overrides
+// and provider values are deep copied, along with its settings, env
+// bindings, loaders, encoders, declarations, providers, hooks and
+// validators. Mutating the copy never affects c. Runtime machinery is not
+// carried over: the copy does not watch the config file or run a reload
+// schedule, has its own event bus without subscribers, an empty history,
+// no OnConfigChange callback and no variables bound with BindVar.
+func (c *Config) Clone() *Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	clone := &Config{
+		defaults:       cloneMap(c.defaults),
+		defaultFuncs:   maps.Clone(c.defaultFuncs),
+		values:         cloneMap(c.values),
+		overrides:      cloneMap(c.overrides),
+		envPrefix:      c.envPrefix,
+		envBindings:    maps.Clone(c.envBindings),
+		envFallback:    append([]string(nil), c.envFallback...),
+		envBare:        append([]string(nil), c.envBare...),
+		cfgName:        c.cfgName,
+		cfgType:        c.cfgType,
+		cfgPaths:       append([]string(nil), c.cfgPaths...),
+		file:           c.file,
+		fileUsed:       c.fileUsed,
+		searched:       append([]string(nil), c.searched...),
+		fallbacks:      append([]string(nil), c.fallbacks...),
+		backups:        c.backups,
+		fileLocking:    c.fileLocking,
+		permCheck:      c.permCheck,
+		verifier:       c.verifier,
+		sigSuffix:      c.sigSuffix,
+		platforms:      c.platforms,
+		faultInjection: c.faultInjection,
+		automatic:      c.automatic,
+		structuredEnv:  c.structuredEnv,
+		locked:         append([]string(nil), c.locked...),
+		pinPatterns:    append([]string(nil), c.pinPatterns...),
+		precedence:     append([]Source(nil), c.precedence...),
+		interceptors:   append([]Interceptor(nil), c.interceptors...),
+		transforms:     append([]transform(nil), c.transforms...),
+		loaders:        maps.Clone(c.loaders),
+		encoders:       maps.Clone(c.encoders),
+		aliases:        maps.Clone(c.aliases),
+		keyAliases:     maps.Clone(c.keyAliases),
+		mimeTypes:      maps.Clone(c.mimeTypes),
+		providers:      append([]providerEntry(nil), c.providers...),
+		providerValues: cloneMap(c.providerValues),
+		raisedValues:   cloneMap(c.raisedValues),
+		configWeight:   c.configWeight,
+		encrypted:      maps.Clone(c.encrypted),
+		required:       append([]string(nil), c.required...),
+		meta:           maps.Clone(c.meta),
+		comments:       maps.Clone(c.comments),
+		secrets:        append([]string(nil), c.secrets...),
+		logger:         c.logger,
+		events:         &EventBus{},
+		hooks:          append([]Hooks(nil), c.hooks...),
+		history:        newChangeHistory(len(c.history.events)),
+		prevValues:     cloneMap(c.prevValues),
+		prevOverrides:  cloneMap(c.prevOverrides),
+		hasPrev:        c.hasPrev,
+		validators:     append([]func(Snapshot) error(nil), c.validators...),
+		coercion:       c.coercion,
+		parseOptions:   c.parseOptions,
+		keyDelim:       c.keyDelim,
+		foldKeys:       c.foldKeys,
+	}
+	if clone.defaults == nil {
+		clone.defaults = make(map[string]any)
+	}
+	if clone.values == nil {
+		clone.values = make(map[string]any)
+	}
+	if clone.envBindings == nil {
+		clone.envBindings = make(map[string]string)
+	}
+	clone.publishLocked()
+	return clone
+}
@@ -0,0 +1,61 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte("db:\n  host: file\n  ports: [1, 2]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.SetDefault("log.level", "info")
+	c.RegisterLoader("fake", fakeLoader{})
+	c.SetConfigFile(file)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var events int
+	c.Subscribe(nil, func(ChangeEvent) { events++ })
+
+	clone := c.Clone()
+	if clone.GetString("db.host") != "file" || clone.GetString("log.level") != "info" {
+		t.Fatalf("expected the clone to hold the same settings, got %v", clone.AllSettings())
+	}
+	clone.Set("db.host", "clone")
+	clone.SetDefault("log.level", "debug")
+	clone.MergeConfigMap(map[string]any{"db": map[string]any{"ports": []any{3}}})
+	if c.GetString("db.host") != "file" || c.GetString("log.level") != "info" || len(c.GetIntSlice("db.ports")) != 2 {
+		t.Fatalf("expected the original to be unaffected, got %v", c.AllSettings())
+	}
+	if events != 0 {
+		t.Fatalf("expected subscribers of the original not to see changes of the clone")
+	}
+	if _, err := clone.Decode([]byte("x"), "fake"); err != nil {
+		t.Fatalf("expected loaders to be copied, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := c.Clone()
+			w.Set("worker", i)
+			_ = w.GetInt("worker")
+		}()
+	}
+	wg.Wait()
+	if err := clone.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
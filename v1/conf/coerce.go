@@ -0,0 +1,268 @@
+package conf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrKeyNotFound is returned by the E getters when a key does not resolve
+// from any source.
+var ErrKeyNotFound = errors.New("conf: key not found")
+
+// CoercionPolicy controls how values that do not match the requested type are
+// treated by the getters.
+type CoercionPolicy int
+
+const (
+	// CoercionWeak converts values on a best-effort basis. Values that cannot
+	// be converted become zero values in the plain getters.
+	CoercionWeak CoercionPolicy = iota
+	// CoercionStrict rejects lossy conversions, such as 3.7 to int, 1 to
+	// bool or "yes" to bool, and records every failed conversion performed by the plain
+	// getters so it can be inspected through CoercionWarnings.
+	CoercionStrict
+)
+
+// ConversionError reports a value that could not be converted to the type
+// requested by a getter.
+type ConversionError struct {
+	Key    string
+	Value  any
+	Target string
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("conf: cannot convert %q value %v (%T) to %s", e.Key, e.Value, e.Value, e.Target)
+}
+
+// SetCoercionPolicy sets the policy used when converting values.
+func (c *Config) SetCoercionPolicy(policy CoercionPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.coercion = policy
+	c.publishLocked()
+}
+
+// CoercionWarnings returns the latest failed conversion recorded for each key
+// under CoercionStrict, ordered by key.
+func (c *Config) CoercionWarnings() []error {
+	c.coerceMu.Lock()
+	defer c.coerceMu.Unlock()
+	keys := make([]string, 0, len(c.coerceWarns))
+	for key := range c.coerceWarns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	warnings := make([]error, len(keys))
+	for i, key := range keys {
+		warnings[i] = c.coerceWarns[key]
+	}
+	return warnings
+}
+
+func (c *Config) noteCoercion(err error) {
+	var cerr *ConversionError
+	if err == nil || !errors.As(err, &cerr) || c.load().coercion != CoercionStrict {
+		return
+	}
+	c.coerceMu.Lock()
+	defer c.coerceMu.Unlock()
+	if c.coerceWarns == nil {
+		c.coerceWarns = make(map[string]error)
+	}
+	c.coerceWarns[cerr.Key] = cerr
+}
+
+func notFound(key string) error {
+	return fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+}
+
+// lossless reports whether converting v to t keeps its meaning, which is what
+// CoercionStrict requires on top of the conversion being possible.
+func (o parseOptions) lossless(v any, t Type) bool {
+	switch t {
+	case TypeInt, TypeDuration:
+		switch val := v.(type) {
+		case float64:
+			return val == math.Trunc(val)
+		case json.Number:
+			return isIntegerNumber(val)
+		}
+	case TypeBool:
+		switch val := v.(type) {
+		case int, int64, float64:
+			return false
+		case string:
+			return o.strictBool(val)
+		}
+	case TypeIntSlice:
+		if items, ok := v.([]any); ok {
+			for _, item := range items {
+				if !o.lossless(item, TypeInt) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// check reports a failed conversion of v to t. Explicit nulls convert to the
+// zero value of any type.
+func (s *state) check(key string, v any, t Type, converted bool) error {
+	if v == nil {
+		return nil
+	}
+	if !converted || (s.coercion == CoercionStrict && !s.lossless(v, t)) {
+		return &ConversionError{Key: key, Value: v, Target: t.String()}
+	}
+	return nil
+}
+
+// GetStringE returns the string value for the key, or an error wrapping
+// ErrKeyNotFound when the key does not resolve.
+func (c *Config) GetStringE(key string) (string, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return "", notFound(key)
+	}
+	return stringify(v), nil
+}
+
+// GetIntE returns the int value for the key, or an error when the key is
+// missing or its value cannot be converted.
+func (c *Config) GetIntE(key string) (int, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return 0, notFound(key)
+	}
+	i, ok := s.toInt(v)
+	if err := s.check(key, v, TypeInt, ok); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
+
+// GetBoolE returns the boolean value for the key, or an error when the key is
+// missing or its value cannot be converted.
+func (c *Config) GetBoolE(key string) (bool, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return false, notFound(key)
+	}
+	b, ok := s.toBool(v)
+	if err := s.check(key, v, TypeBool, ok); err != nil {
+		return false, err
+	}
+	return b, nil
+}
+
+// GetFloat64E returns the float64 value for the key, or an error when the key
+// is missing or its value cannot be converted.
+func (c *Config) GetFloat64E(key string) (float64, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return 0, notFound(key)
+	}
+	f, ok := s.toFloat64(v)
+	if err := s.check(key, v, TypeFloat, ok); err != nil {
+		return 0, err
+	}
+	return f, nil
+}
+
+// GetDurationE returns the time.Duration value for the key, or an error when
+// the key is missing or its value cannot be converted.
+func (c *Config) GetDurationE(key string) (time.Duration, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return 0, notFound(key)
+	}
+	d, ok := s.toDuration(v)
+	if err := s.check(key, v, TypeDuration, ok); err != nil {
+		return 0, err
+	}
+	return d, nil
+}
+
+// GetStringSliceE returns the []string value for the key, or an error when
+// the key is missing or its value is not a list or a comma separated string.
+func (c *Config) GetStringSliceE(key string) ([]string, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res := toStringSlice(v)
+	if err := s.check(key, v, TypeStringSlice, res != nil); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetIntSliceE returns the []int value for the key, or an error when the key
+// is missing or any element cannot be converted.
+func (c *Config) GetIntSliceE(key string) ([]int, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res, ok := s.toIntSlice(v)
+	if err := s.check(key, v, TypeIntSlice, ok); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetStringMapE returns the map[string]any value for the key, or an error
+// when the key is missing or its value is not a map.
+func (c *Config) GetStringMapE(key string) (map[string]any, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res, ok := toStringMap(v)
+	if !ok && v != nil {
+		return nil, &ConversionError{Key: key, Value: v, Target: "map[string]any"}
+	}
+	return res, nil
+}
+
+// GetStringMapStringE returns the map[string]string value for the key, or an
+// error when the key is missing or its value is not a map.
+func (c *Config) GetStringMapStringE(key string) (map[string]string, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res, ok := toStringMapString(v)
+	if !ok && v != nil {
+		return nil, &ConversionError{Key: key, Value: v, Target: "map[string]string"}
+	}
+	return res, nil
+}
+
+// GetStringMapStringSliceE returns the map[string][]string value for the
+// key, or an error when the key is missing or its value is not a map of
+// lists.
+func (c *Config) GetStringMapStringSliceE(key string) (map[string][]string, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res, ok := toStringMapStringSlice(v)
+	if !ok && v != nil {
+		return nil, &ConversionError{Key: key, Value: v, Target: "map[string][]string"}
+	}
+	return res, nil
+}
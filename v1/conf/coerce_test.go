@@ -0,0 +1,148 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEGetters(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"port":  "abc",
+		"debug": "2",
+		"ratio": 3.7,
+	})
+
+	if _, err := c.GetIntE("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	var cerr *ConversionError
+	if _, err := c.GetIntE("port"); !errors.As(err, &cerr) || cerr.Key != "port" || cerr.Target != "int" {
+		t.Fatalf("expected conversion error for port, got %v", err)
+	}
+	if _, err := c.GetBoolE("debug"); !errors.As(err, &cerr) {
+		t.Fatalf("expected conversion error for debug, got %v", err)
+	}
+	if got, err := c.GetIntE("ratio"); err != nil || got != 3 {
+		t.Fatalf("expected weak conversion of 3.7 to 3, got %d, %v", got, err)
+	}
+	if got := c.GetInt("port"); got != 0 {
+		t.Fatalf("expected plain getter to fall back to 0, got %d", got)
+	}
+	if warnings := c.CoercionWarnings(); len(warnings) != 0 {
+		t.Fatalf("expected no warnings under weak policy, got %v", warnings)
+	}
+}
+
+func TestStrictCoercion(t *testing.T) {
+	c := New()
+	c.SetCoercionPolicy(CoercionStrict)
+	c.MergeConfigMap(map[string]any{
+		"port":    "abc",
+		"ratio":   3.7,
+		"enabled": 1,
+	})
+
+	var cerr *ConversionError
+	if _, err := c.GetIntE("ratio"); !errors.As(err, &cerr) {
+		t.Fatalf("expected lossy conversion to fail, got %v", err)
+	}
+	if _, err := c.GetBoolE("enabled"); !errors.As(err, &cerr) {
+		t.Fatalf("expected numeric bool to fail, got %v", err)
+	}
+
+	_ = c.GetInt("port")
+	_ = c.GetBool("enabled")
+	_ = c.GetInt("missing")
+	warnings := c.CoercionWarnings()
+	if len(warnings) != 2 {
+		t.Fatalf("expected two recorded warnings, got %v", warnings)
+	}
+	if !errors.As(warnings[0], &cerr) || cerr.Key != "enabled" {
+		t.Fatalf("expected warnings ordered by key, got %v", warnings)
+	}
+}
+
+func TestExtendedBool(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"a": "yes",
+		"b": "Off",
+		"c": "Y",
+		"d": "maybe",
+	})
+
+	if !c.GetBool("a") || c.GetBool("b") || !c.GetBool("c") {
+		t.Fatalf("expected yes/off/y to be parsed")
+	}
+	if _, err := c.GetBoolE("d"); err == nil {
+		t.Fatalf("expected error for unknown boolean spelling")
+	}
+
+	c.SetCoercionPolicy(CoercionStrict)
+	if _, err := c.GetBoolE("a"); err == nil {
+		t.Fatalf("expected strict policy to reject yes")
+	}
+}
+
+func TestBoolWords(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"a": "Enabled",
+		"b": "disabled",
+		"c": "yes",
+	})
+
+	if err := c.SetBoolWords([]string{"on"}, []string{"ON"}); err == nil {
+		t.Fatalf("expected a word listed twice to be rejected")
+	}
+	if err := c.SetBoolWords([]string{"enabled"}, []string{"disabled"}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.GetBool("a") || c.GetBool("b") {
+		t.Fatalf("expected enabled/disabled to be parsed")
+	}
+	if _, err := c.GetBoolE("c"); err == nil {
+		t.Fatalf("expected yes to be rejected once the words are replaced")
+	}
+	c.SetCoercionPolicy(CoercionStrict)
+	if b, err := c.GetBoolE("a"); err != nil || !b {
+		t.Fatalf("expected strict policy to accept the configured words, got %v", err)
+	}
+
+	if err := c.SetBoolWords(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	c.SetCoercionPolicy(CoercionWeak)
+	if !c.GetBool("c") {
+		t.Fatalf("expected the default words to be restored")
+	}
+}
+
+func TestMapEGetters(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"labels": map[string]any{"team": "core", "tier": 1},
+		"groups": map[string]any{"admins": []any{"ada", "linus"}},
+		"name":   "checkout",
+	})
+
+	labels, err := c.GetStringMapStringE("labels")
+	if err != nil || labels["tier"] != "1" {
+		t.Fatalf("expected labels, got %v, %v", labels, err)
+	}
+	groups, err := c.GetStringMapStringSliceE("groups")
+	if err != nil || len(groups["admins"]) != 2 {
+		t.Fatalf("expected groups, got %v, %v", groups, err)
+	}
+	var convErr *ConversionError
+	if _, err := c.GetStringMapE("name"); !errors.As(err, &convErr) || convErr.Key != "name" || convErr.Value != "checkout" {
+		t.Fatalf("expected conversion error reporting the key and value, got %v", err)
+	}
+	if _, err := c.GetStringMapStringE("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+	if m := c.GetStringMap("name"); m == nil || len(m) != 0 {
+		t.Fatalf("expected plain getter to keep returning an empty map")
+	}
+}
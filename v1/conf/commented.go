@@ -0,0 +1,185 @@
+package conf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	ini "gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// encodeCommentedLocked serializes the nested values map in format, writing
+// the comment lines registered for a key (path joined with the key
+// delimiter) above it. YAML, TOML
+// and INI support comments; other formats fall back to the registered
+// encoder and the comments are dropped. Callers must hold c.mu.
+func (c *Config) encodeCommentedLocked(format string, values map[string]any, comments map[string][]string) ([]byte, error) {
+	switch format = c.formatLocked(format); format {
+	case "yaml", "yml":
+		return encodeCommentedYAML(values, comments, c.delimLocked())
+	case "toml":
+		return encodeCommentedTOML(values, comments, c.delimLocked())
+	case "ini":
+		return encodeCommentedINI(values, comments, c.delimLocked())
+	}
+	encoder, ok := c.encoders[format]
+	if !ok || encoder == nil {
+		return nil, ErrUnsupportedFormat
+	}
+	return encoder.Encode(values)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(prefix, key, sep string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + sep + key
+}
+
+// plainValue converts values the encoders do not render in a readable way.
+func plainValue(v any) any {
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+	return v
+}
+
+func encodeCommentedYAML(values map[string]any, comments map[string][]string, sep string) ([]byte, error) {
+	root, err := yamlMapping("", values, comments, sep)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func yamlMapping(prefix string, values map[string]any, comments map[string][]string, sep string) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range sortedKeys(values) {
+		path := joinPath(prefix, key, sep)
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+		keyNode.HeadComment = strings.Join(comments[path], "\n")
+		var valueNode *yaml.Node
+		if sub, ok := values[key].(map[string]any); ok && len(sub) > 0 {
+			var err error
+			if valueNode, err = yamlMapping(path, sub, comments, sep); err != nil {
+				return nil, err
+			}
+		} else {
+			valueNode = &yaml.Node{}
+			if err := valueNode.Encode(numberLiterals(plainValue(values[key]), yamlNumber)); err != nil {
+				return nil, err
+			}
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node, nil
+}
+
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if bareTOMLKey.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+func encodeCommentedTOML(values map[string]any, comments map[string][]string, sep string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeTOMLTable(&buf, "", nil, values, comments, sep); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeTOMLComments(buf *bytes.Buffer, lines []string) {
+	for _, line := range lines {
+		buf.WriteString("# " + line + "\n")
+	}
+}
+
+func writeTOMLTable(buf *bytes.Buffer, prefix string, header []string, values map[string]any, comments map[string][]string, sep string) error {
+	var tables []string
+	var leaves []string
+	for _, key := range sortedKeys(values) {
+		if sub, ok := values[key].(map[string]any); ok && len(sub) > 0 {
+			tables = append(tables, key)
+		} else {
+			leaves = append(leaves, key)
+		}
+	}
+	if len(header) > 0 {
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		writeTOMLComments(buf, comments[prefix])
+		buf.WriteString("[" + strings.Join(header, ".") + "]\n")
+	}
+	for _, key := range leaves {
+		path := joinPath(prefix, key, sep)
+		writeTOMLComments(buf, comments[path])
+		var line bytes.Buffer
+		err := toml.NewEncoder(&line).Encode(map[string]any{key: numberLiterals(plainValue(values[key]), newTOMLNumber)})
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		buf.Write(line.Bytes())
+	}
+	for _, key := range tables {
+		path := joinPath(prefix, key, sep)
+		next := append(append([]string(nil), header...), tomlKey(key))
+		if err := writeTOMLTable(buf, path, next, values[key].(map[string]any), comments, sep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeCommentedINI(values map[string]any, comments map[string][]string, sep string) ([]byte, error) {
+	flat := make(map[string]any)
+	flattenInto("", values, flat, sep)
+	file := ini.Empty()
+	section := file.Section("")
+	for _, key := range sortedKeys(flat) {
+		value := plainValue(flat[key])
+		if items, ok := value.([]any); ok {
+			value = strings.Join(toStringSlice(items), ",")
+		}
+		k, err := section.NewKey(key, stringify(value))
+		if err != nil {
+			return nil, err
+		}
+		if lines := comments[key]; len(lines) > 0 {
+			k.Comment = "; " + strings.Join(lines, "\n; ")
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := file.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
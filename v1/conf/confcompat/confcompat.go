@@ -0,0 +1,83 @@
+// Package confcompat copies settings between a Viper instance and a
+// conf.Config, easing an incremental migration of code bases built on
+// github.com/spf13/viper.
+//
+// Viper keeps its defaults, overrides and environment bindings private, so
+// FromViper copies the values read from the config file as config values and
+// every other key, resolved, as a default. The environment prefix and the
+// config file carry over; AutomaticEnv and explicit bindings have to be
+// declared again on the Config.
+package confcompat
+
+import (
+	"strings"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"github.com/spf13/viper"
+)
+
+// FromViper returns a Config holding the settings of v at the time of the
+// call. The copy is a snapshot: later Set calls, reloads or WatchConfig
+// changes on v do not reach the Config, which reads its config file again
+// only when ReadInConfig is called on it.
+func FromViper(v *viper.Viper) *conf.Config {
+	c := conf.New()
+	if file := v.ConfigFileUsed(); file != "" {
+		c.SetConfigFile(file)
+	}
+	if prefix := v.GetEnvPrefix(); prefix != "" {
+		c.SetEnvPrefix(strings.ToUpper(prefix))
+	}
+	sep := c.KeyDelimiter()
+	values := make(map[string]any)
+	for _, key := range v.AllKeys() {
+		if v.InConfig(key) {
+			conf.SetPath(values, key, sep, v.Get(key))
+			continue
+		}
+		c.SetDefault(key, v.Get(key))
+	}
+	c.MergeConfigMap(values)
+	return c
+}
+
+// ToViper returns a Viper instance holding the settings of cfg: its defaults
+// as defaults, its provider and config file values as config, its overrides
+// as overrides, its environment prefix and an environment binding for every
+// known key. AutomaticEnv is enabled, since cfg consults the environment for
+// any key. Viper has no provider layer, so provider values rank as config
+// values. The Viper instance uses the key delimiter of cfg and, like
+// FromViper, is a snapshot of cfg at the time of the call.
+func ToViper(cfg *conf.Config) *viper.Viper {
+	snap := cfg.Snapshot()
+	sep := snap.KeyDelimiter()
+	v := viper.NewWithOptions(viper.KeyDelimiter(sep))
+	for key, value := range conf.Flatten(snap.Layer(conf.SourceDefault), sep) {
+		v.SetDefault(key, value)
+	}
+	v.MergeConfigMap(snap.Layer(conf.SourceProvider))
+	v.MergeConfigMap(snap.Layer(conf.SourceConfig))
+	for key, value := range conf.Flatten(snap.Layer(conf.SourceOverride), sep) {
+		v.Set(key, value)
+	}
+	for key, env := range cfg.EnvBindings() {
+		v.BindEnv(key, env)
+	}
+	var file, prefix string
+	var prefixed bool
+	for _, src := range cfg.Sources() {
+		switch {
+		case src.Source == conf.SourceConfig && src.Used && file == "":
+			file = src.Name
+		case src.Source == conf.SourceEnv && !prefixed:
+			prefix, prefixed = src.Name, true
+		}
+	}
+	if file != "" {
+		v.SetConfigFile(file)
+	}
+	v.SetEnvPrefix(prefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(sep, "_"))
+	v.AutomaticEnv()
+	return v
+}
@@ -0,0 +1,73 @@
+package confcompat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"github.com/spf13/viper"
+)
+
+func TestFromViper(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte("db:\n  host: file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	v.SetDefault("db.port", 5432)
+	v.SetEnvPrefix("app")
+
+	c := FromViper(v)
+	if got := c.GetString("db.host"); got != "file" {
+		t.Fatalf("expected config value from viper, got %q", got)
+	}
+	if got := c.GetInt("db.port"); got != 5432 {
+		t.Fatalf("expected default from viper, got %d", got)
+	}
+	if layer := c.Snapshot().Layer(conf.SourceDefault); layer["db"] == nil {
+		t.Fatalf("expected db.port to be a default, got %v", layer)
+	}
+	t.Setenv("APP_DB_PORT", "6543")
+	if got := c.GetInt("db.port"); got != 6543 {
+		t.Fatalf("expected the env prefix to carry over, got %d", got)
+	}
+}
+
+func TestToViper(t *testing.T) {
+	c := conf.New()
+	c.SetDefault("db.port", 5432)
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"host": "file"}})
+	c.Set("db.pool", 10)
+	c.BindEnv("db.user", "DATABASE_USER")
+	t.Setenv("DATABASE_USER", "admin")
+
+	v := ToViper(c)
+	if got := v.GetInt("db.port"); got != 5432 {
+		t.Fatalf("expected default in viper, got %d", got)
+	}
+	if got := v.GetString("db.host"); got != "file" || !v.InConfig("db.host") {
+		t.Fatalf("expected config value in viper, got %q", got)
+	}
+	if got := v.GetInt("db.pool"); got != 10 {
+		t.Fatalf("expected override in viper, got %d", got)
+	}
+	if got := v.GetString("db.user"); got != "admin" {
+		t.Fatalf("expected env binding in viper, got %q", got)
+	}
+}
+
+func TestToViperEnv(t *testing.T) {
+	c := conf.New()
+	c.SetEnvPrefix("APP")
+	t.Setenv("APP_CACHE_TTL", "30")
+
+	v := ToViper(c)
+	if got := v.GetInt("cache.ttl"); got != 30 {
+		t.Fatalf("expected prefixed env for an unknown key in viper, got %d", got)
+	}
+}
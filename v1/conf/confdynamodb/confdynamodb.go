@@ -0,0 +1,225 @@
+// Package confdynamodb provides a conf.Provider reading the configuration
+// from the items of a DynamoDB table sharing a partition key, typically one
+// partition per application and environment such as "app/production".
+//
+// An item of the partition is either a key/value row, whose key attribute
+// holds a configuration key ("server.port") and value attribute its value,
+// or a blob holding a whole document decoded with a conf.Decoder. Items are
+// merged in sort key order, later ones overriding earlier ones. Keys are
+// split into levels at the key delimiter of the Config loading the provider,
+// see conf.KeyDelimiterFromContext.
+package confdynamodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// API is the part of *dynamodb.Client used by the provider.
+type API interface {
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// ChangeSource signals modifications of the table. Stream implements it with
+// DynamoDB Streams; callers with a change feed of their own can adapt it to
+// this interface as well.
+type ChangeSource interface {
+	// Changes returns a channel receiving a value after every modification,
+	// closed when ctx is done.
+	Changes(ctx context.Context) (<-chan struct{}, error)
+}
+
+// Options configures a Provider.
+type Options struct {
+	// Table is the name of the table.
+	Table string
+	// PartitionKey is the name of the partition key attribute, "pk" unless
+	// set, and Partition the value selecting the configuration items.
+	PartitionKey string
+	Partition    string
+	// KeyAttribute and ValueAttribute name the attributes of key/value rows,
+	// "key" and "value" unless set.
+	KeyAttribute   string
+	ValueAttribute string
+	// BlobAttribute names the attribute holding a whole document, "config"
+	// unless set, and Format its format, an extension or a MIME type.
+	BlobAttribute string
+	Format        string
+	// Decoder decodes blobs, usually the Config the provider is added to.
+	Decoder conf.Decoder
+	// Changes enables push-based change detection, while PollInterval
+	// reloads the items periodically when Changes is nil.
+	Changes      ChangeSource
+	PollInterval time.Duration
+}
+
+// Provider reads the configuration items of a partition.
+type Provider struct {
+	api  API
+	opts Options
+}
+
+// New returns a provider querying the table through api, usually a
+// *dynamodb.Client.
+func New(api API, opts Options) *Provider {
+	if opts.PartitionKey == "" {
+		opts.PartitionKey = "pk"
+	}
+	if opts.KeyAttribute == "" {
+		opts.KeyAttribute = "key"
+	}
+	if opts.ValueAttribute == "" {
+		opts.ValueAttribute = "value"
+	}
+	if opts.BlobAttribute == "" {
+		opts.BlobAttribute = "config"
+	}
+	return &Provider{api: api, opts: opts}
+}
+
+// Name identifies the table and partition.
+func (p *Provider) Name() string {
+	return "dynamodb:" + p.opts.Table + "/" + p.opts.Partition
+}
+
+// Load queries every item of the partition and merges them.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	sep := conf.KeyDelimiterFromContext(ctx)
+	values := make(map[string]any)
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(p.opts.Table),
+		KeyConditionExpression:    aws.String("#pk = :pk"),
+		ExpressionAttributeNames:  map[string]string{"#pk": p.opts.PartitionKey},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":pk": &types.AttributeValueMemberS{Value: p.opts.Partition}},
+		ConsistentRead:            aws.Bool(true),
+	}
+	for {
+		out, err := p.api.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range out.Items {
+			if err := p.merge(values, item, sep); err != nil {
+				return nil, err
+			}
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return values, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+func (p *Provider) merge(values map[string]any, item map[string]types.AttributeValue, sep string) error {
+	if blob, ok := item[p.opts.BlobAttribute]; ok {
+		if p.opts.Decoder == nil {
+			return errors.New("no decoder set")
+		}
+		var data []byte
+		switch v := blob.(type) {
+		case *types.AttributeValueMemberS:
+			data = []byte(v.Value)
+		case *types.AttributeValueMemberB:
+			data = v.Value
+		default:
+			return errors.New("blob attribute must be a string or binary")
+		}
+		doc, err := p.opts.Decoder.Decode(data, p.opts.Format)
+		if err != nil {
+			return err
+		}
+		conf.MergeMaps(values, doc)
+		return nil
+	}
+	key, ok := item[p.opts.KeyAttribute].(*types.AttributeValueMemberS)
+	if !ok || key.Value == "" {
+		return nil
+	}
+	value, ok := item[p.opts.ValueAttribute]
+	if !ok {
+		return nil
+	}
+	conf.SetPath(values, key.Value, sep, fromAttribute(value))
+	return nil
+}
+
+// Watch calls notify after every change signaled by Options.Changes, or
+// every Options.PollInterval, until ctx is done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	if p.opts.Changes != nil {
+		changes, err := p.opts.Changes.Changes(ctx)
+		if err != nil {
+			return err
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case _, ok := <-changes:
+				if !ok {
+					return nil
+				}
+				notify()
+			}
+		}
+	}
+	if p.opts.PollInterval <= 0 {
+		return errors.New("no change detection configured")
+	}
+	ticker := time.NewTicker(p.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			notify()
+		}
+	}
+}
+
+// fromAttribute converts an attribute value to the types used by conf.
+// Numbers are kept as text and converted by the getters.
+func fromAttribute(av types.AttributeValue) any {
+	switch v := av.(type) {
+	case *types.AttributeValueMemberS:
+		return v.Value
+	case *types.AttributeValueMemberN:
+		return v.Value
+	case *types.AttributeValueMemberBOOL:
+		return v.Value
+	case *types.AttributeValueMemberB:
+		return string(v.Value)
+	case *types.AttributeValueMemberSS:
+		return toList(v.Value)
+	case *types.AttributeValueMemberNS:
+		return toList(v.Value)
+	case *types.AttributeValueMemberL:
+		list := make([]any, len(v.Value))
+		for i, item := range v.Value {
+			list[i] = fromAttribute(item)
+		}
+		return list
+	case *types.AttributeValueMemberM:
+		m := make(map[string]any, len(v.Value))
+		for k, item := range v.Value {
+			m[k] = fromAttribute(item)
+		}
+		return m
+	}
+	return nil
+}
+
+func toList(items []string) []any {
+	list := make([]any, len(items))
+	for i, item := range items {
+		list[i] = item
+	}
+	return list
+}
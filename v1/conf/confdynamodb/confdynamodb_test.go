@@ -0,0 +1,56 @@
+package confdynamodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// pagedTable returns one page of items per Query call.
+type pagedTable struct {
+	pages [][]map[string]types.AttributeValue
+	calls int
+}
+
+func (t *pagedTable) Query(ctx context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if pk := in.ExpressionAttributeValues[":pk"].(*types.AttributeValueMemberS).Value; pk != "app/prod" {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	out := &dynamodb.QueryOutput{Items: t.pages[t.calls]}
+	t.calls++
+	if t.calls < len(t.pages) {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "app/prod"}}
+	}
+	return out, nil
+}
+
+func TestProviderLoad(t *testing.T) {
+	table := &pagedTable{pages: [][]map[string]types.AttributeValue{
+		{
+			{"config": &types.AttributeValueMemberS{Value: "server:\n  port: 8080\n  host: localhost\n"}},
+		},
+		{
+			{"key": &types.AttributeValueMemberS{Value: "server.port"}, "value": &types.AttributeValueMemberN{Value: "9090"}},
+			{"key": &types.AttributeValueMemberS{Value: "features"}, "value": &types.AttributeValueMemberSS{Value: []string{"a", "b"}}},
+			{"key": &types.AttributeValueMemberS{Value: "debug"}, "value": &types.AttributeValueMemberBOOL{Value: true}},
+		},
+	}}
+
+	cfg := conf.New()
+	cfg.AddProvider(New(table, Options{Table: "config", Partition: "app/prod", Format: "yaml", Decoder: cfg}))
+	if err := cfg.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if table.calls != 2 {
+		t.Fatalf("expected both pages to be queried, got %d calls", table.calls)
+	}
+	if cfg.GetInt("server.port") != 9090 || cfg.GetString("server.host") != "localhost" {
+		t.Fatalf("expected rows to override the blob, got %v", cfg.AllSettingsFlat())
+	}
+	if got := cfg.GetStringSlice("features"); len(got) != 2 || !cfg.GetBool("debug") {
+		t.Fatalf("unexpected values %v", cfg.AllSettingsFlat())
+	}
+}
@@ -0,0 +1,143 @@
+package confdynamodb
+
+import (
+	"context"
+	"time"
+)
+
+// StreamsAPI is the part of the DynamoDB Streams API used by Stream. It is
+// kept free of the Streams client types so that the package does not depend
+// on it; wrapping a *dynamodbstreams.Client takes a few lines, see the
+// documentation of the package.
+type StreamsAPI interface {
+	// Shards returns the IDs of the shards of the stream, as returned by
+	// DescribeStream.
+	Shards(ctx context.Context, streamARN string) ([]string, error)
+	// ShardIterator returns an iterator over the shard, positioned after
+	// its latest record when latest is set and at its oldest record
+	// otherwise, as returned by GetShardIterator.
+	ShardIterator(ctx context.Context, streamARN, shardID string, latest bool) (string, error)
+	// Records returns the partition key values of the items changed by the
+	// records read from iterator, and the iterator to read next, empty once
+	// the shard is closed, as returned by GetRecords.
+	Records(ctx context.Context, iterator string) (partitions []string, next string, err error)
+}
+
+// DefaultStreamInterval is the interval at which a Stream reads its shards
+// unless set.
+const DefaultStreamInterval = time.Second
+
+// Stream is a ChangeSource reading the DynamoDB Streams stream of the table,
+// which must be enabled with any view type. Only the changes to the items of
+// Partition are signaled, or every change when Partition is empty.
+type Stream struct {
+	API       StreamsAPI
+	StreamARN string
+	Partition string
+	// Interval is the pause between two reads of the shards,
+	// DefaultStreamInterval unless set.
+	Interval time.Duration
+}
+
+// Changes starts reading the stream from its latest records. Shards that
+// close are followed by their children, read from their oldest record so
+// that no change is missed. When a shard cannot be read, it is read again
+// from its latest record and a change is signaled, so that the partition is
+// reloaded.
+func (s *Stream) Changes(ctx context.Context) (<-chan struct{}, error) {
+	shards, err := s.API.Shards(ctx, s.StreamARN)
+	if err != nil {
+		return nil, err
+	}
+	iterators := make(map[string]string, len(shards))
+	seen := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		it, err := s.API.ShardIterator(ctx, s.StreamARN, shard, true)
+		if err != nil {
+			return nil, err
+		}
+		iterators[shard] = it
+		seen[shard] = true
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		following := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			changed, closed := s.read(ctx, iterators)
+			if changed {
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+			if closed || following {
+				following = !s.follow(ctx, iterators, seen)
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// read reads the new records of every open shard and reports whether one of
+// them changed the partition and whether a shard closed. Closed shards are
+// removed from iterators.
+func (s *Stream) read(ctx context.Context, iterators map[string]string) (changed, closed bool) {
+	for shard, it := range iterators {
+		partitions, next, err := s.API.Records(ctx, it)
+		if err != nil {
+			// The iterator may have expired: start over from the latest
+			// record and have the partition reloaded, as changes may have
+			// been missed in between.
+			if it, err := s.API.ShardIterator(ctx, s.StreamARN, shard, true); err == nil {
+				iterators[shard] = it
+				changed = true
+			}
+			continue
+		}
+		for _, partition := range partitions {
+			if s.Partition == "" || partition == s.Partition {
+				changed = true
+			}
+		}
+		if next == "" {
+			delete(iterators, shard)
+			closed = true
+		} else {
+			iterators[shard] = next
+		}
+	}
+	return changed, closed
+}
+
+// follow adds the shards created since the last call, which replace the
+// closed ones, and reports whether it succeeded.
+func (s *Stream) follow(ctx context.Context, iterators map[string]string, seen map[string]bool) bool {
+	shards, err := s.API.Shards(ctx, s.StreamARN)
+	if err != nil {
+		return false
+	}
+	for _, shard := range shards {
+		if seen[shard] {
+			continue
+		}
+		it, err := s.API.ShardIterator(ctx, s.StreamARN, shard, false)
+		if err != nil {
+			return false
+		}
+		iterators[shard] = it
+		seen[shard] = true
+	}
+	return true
+}
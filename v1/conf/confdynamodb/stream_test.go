@@ -0,0 +1,114 @@
+package confdynamodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStream serves the records queued on its shards, one batch per read.
+type fakeStream struct {
+	mu      sync.Mutex
+	shards  []string
+	batches map[string][][]string
+	closed  map[string]bool
+	fail    map[string]bool
+}
+
+func (f *fakeStream) Shards(context.Context, string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.shards...), nil
+}
+
+func (f *fakeStream) ShardIterator(_ context.Context, _, shard string, latest bool) (string, error) {
+	if latest {
+		return shard + ":latest", nil
+	}
+	return shard + ":oldest", nil
+}
+
+func (f *fakeStream) Records(_ context.Context, it string) ([]string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	shard, _, _ := strings.Cut(it, ":")
+	if f.fail[shard] {
+		delete(f.fail, shard)
+		return nil, "", errors.New("expired iterator")
+	}
+	var records []string
+	if batches := f.batches[shard]; len(batches) > 0 {
+		records, f.batches[shard] = batches[0], batches[1:]
+	}
+	if f.closed[shard] && len(f.batches[shard]) == 0 {
+		return records, "", nil
+	}
+	return records, it, nil
+}
+
+func (f *fakeStream) push(shard string, partitions ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches[shard] = append(f.batches[shard], partitions)
+}
+
+func expectChange(t *testing.T, changes <-chan struct{}, want bool) {
+	t.Helper()
+	select {
+	case <-changes:
+		if !want {
+			t.Fatalf("expected no change to be signaled")
+		}
+	case <-time.After(100 * time.Millisecond):
+		if want {
+			t.Fatalf("expected a change to be signaled")
+		}
+	}
+}
+
+func TestStreamChanges(t *testing.T) {
+	api := &fakeStream{
+		shards:  []string{"a"},
+		batches: map[string][][]string{},
+		closed:  map[string]bool{},
+		fail:    map[string]bool{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Stream{API: api, StreamARN: "arn", Partition: "app/prod", Interval: 5 * time.Millisecond}
+	changes, err := s.Changes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api.push("a", "app/dev")
+	expectChange(t, changes, false)
+	api.push("a", "app/dev", "app/prod")
+	expectChange(t, changes, true)
+
+	// The shard rolls over to a child, read from its oldest record.
+	api.mu.Lock()
+	api.shards = append(api.shards, "b")
+	api.closed["a"] = true
+	api.batches["b"] = [][]string{{"app/prod"}}
+	api.mu.Unlock()
+	expectChange(t, changes, true)
+
+	api.mu.Lock()
+	api.fail["b"] = true
+	api.mu.Unlock()
+	expectChange(t, changes, true)
+
+	cancel()
+	select {
+	case _, ok := <-changes:
+		if ok {
+			<-changes
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the channel to be closed with the context")
+	}
+}
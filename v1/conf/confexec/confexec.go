@@ -0,0 +1,185 @@
+// Package confexec provides a conf.Provider running a command and decoding
+// its standard output as configuration, for secret fetchers such as
+// "op inject" or in-house scripts.
+//
+// Running commands is a capability the core package deliberately lacks:
+// importing this package and adding a provider is the explicit opt-in. The
+// command is executed directly, never through a shell, with a timeout and a
+// scrubbed environment holding only the variables listed in Options.Env.
+package confexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// DefaultTimeout bounds a run when Options.Timeout is zero.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxOutput bounds the standard output when Options.MaxOutput is
+// zero.
+const DefaultMaxOutput = 4 << 20
+
+// maxStderr bounds the standard error quoted in errors.
+const maxStderr = 1024
+
+// waitDelay bounds how long a run waits for the output of a killed command,
+// which a child process inheriting it could otherwise hold open forever.
+const waitDelay = time.Second
+
+// Options configures a Provider.
+type Options struct {
+	// Command is the program to run, looked up in PATH, with Args as its
+	// arguments.
+	Command string
+	Args    []string
+	// Dir is the working directory of the command, the current one if empty.
+	Dir string
+	// Env lists the environment of the command: "NAME" passes the variable
+	// through from the current environment, "NAME=value" sets it. Nothing
+	// else is inherited.
+	Env []string
+	// Timeout bounds every run, DefaultTimeout if zero. The command is killed
+	// when it expires.
+	Timeout time.Duration
+	// MaxOutput bounds the standard output in bytes, DefaultMaxOutput if
+	// zero. The command is killed and the load fails when it writes more.
+	MaxOutput int
+	// Format is the format of the output, an extension or a MIME type,
+	// "json" by default, decoded with Decoder, usually the Config the
+	// provider is added to.
+	Format  string
+	Decoder conf.Decoder
+	// RefreshInterval makes Watch run the command again periodically.
+	RefreshInterval time.Duration
+}
+
+// Provider runs a command and decodes its output.
+type Provider struct {
+	opts Options
+}
+
+// New returns a provider running opts.Command.
+func New(opts Options) *Provider {
+	return &Provider{opts: opts}
+}
+
+// Name returns the command of the provider.
+func (p *Provider) Name() string {
+	return "exec:" + p.opts.Command
+}
+
+// Load runs the command and decodes its standard output. A non-zero exit
+// status fails the load, quoting the start of the standard error.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	if p.opts.Command == "" {
+		return nil, errors.New("no command set")
+	}
+	if p.opts.Decoder == nil {
+		return nil, errors.New("no decoder set")
+	}
+	timeout := p.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	limit := p.opts.MaxOutput
+	if limit <= 0 {
+		limit = DefaultMaxOutput
+	}
+
+	cmd := exec.CommandContext(ctx, p.opts.Command, p.opts.Args...)
+	cmd.Dir = p.opts.Dir
+	cmd.Env = environ(p.opts.Env)
+	cmd.WaitDelay = waitDelay
+	stdout := &cappedBuffer{limit: limit, overflow: cancel}
+	stderr := &cappedBuffer{limit: maxStderr}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if stdout.truncated {
+		return nil, fmt.Errorf("%s: output exceeds %d bytes", p.opts.Command, limit)
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%s: timed out after %s", p.opts.Command, timeout)
+		}
+		msg := strings.TrimSpace(stderr.buf.String())
+		if stderr.truncated {
+			msg += "..."
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", p.opts.Command, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", p.opts.Command, err)
+	}
+	format := p.opts.Format
+	if format == "" {
+		format = "json"
+	}
+	return p.opts.Decoder.Decode(stdout.buf.Bytes(), format)
+}
+
+// Watch calls notify every Options.RefreshInterval until ctx is done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	if p.opts.RefreshInterval <= 0 {
+		return errors.New("no refresh interval configured")
+	}
+	ticker := time.NewTicker(p.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			notify()
+		}
+	}
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, calling overflow, if set, the first time it does.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+	overflow  func()
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		if !b.truncated && b.overflow != nil {
+			b.overflow()
+		}
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// environ builds the scrubbed environment described by spec. It is never
+// nil, so the command does not inherit the current environment.
+func environ(spec []string) []string {
+	env := []string{}
+	for _, entry := range spec {
+		if strings.Contains(entry, "=") {
+			env = append(env, entry)
+			continue
+		}
+		if v, ok := os.LookupEnv(entry); ok {
+			env = append(env, entry+"="+v)
+		}
+	}
+	return env
+}
@@ -0,0 +1,98 @@
+package confexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// TestHelperProcess is run as the command by the other tests.
+func TestHelperProcess(t *testing.T) {
+	switch os.Getenv("CONFEXEC_HELPER") {
+	case "print":
+		fmt.Printf("db:\n  password: %q\nleaked: %q\n", os.Getenv("DB_PASSWORD"), os.Getenv("CONFEXEC_SECRET"))
+		os.Exit(0)
+	case "fail":
+		fmt.Fprintln(os.Stderr, "vault sealed")
+		os.Exit(3)
+	case "flood":
+		fmt.Print(strings.Repeat("x", 1<<20))
+		os.Exit(0)
+	case "orphan":
+		// The grandchild inherits the output and outlives the command.
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+		cmd.Env = []string{"CONFEXEC_HELPER=sleep"}
+		cmd.Stdout = os.Stdout
+		cmd.Start()
+		time.Sleep(10 * time.Second)
+		os.Exit(0)
+	case "sleep":
+		time.Sleep(10 * time.Second)
+		os.Exit(0)
+	}
+}
+
+func helper(mode string, env ...string) Options {
+	return Options{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^TestHelperProcess$"},
+		Env:     append([]string{"CONFEXEC_HELPER=" + mode}, env...),
+		Format:  "yaml",
+	}
+}
+
+func TestProvider(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "hunter2")
+	t.Setenv("CONFEXEC_SECRET", "do-not-pass")
+
+	cfg := conf.New()
+	opts := helper("print", "DB_PASSWORD")
+	opts.Decoder = cfg
+	cfg.AddProvider(New(opts))
+	if err := cfg.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetString("db.password") != "hunter2" {
+		t.Fatalf("expected allowed variable to reach the command, got %q", cfg.GetString("db.password"))
+	}
+	if cfg.GetString("leaked") != "" {
+		t.Fatalf("expected environment to be scrubbed")
+	}
+
+	opts = helper("fail")
+	opts.Decoder = cfg
+	if _, err := New(opts).Load(context.Background()); err == nil || !strings.Contains(err.Error(), "vault sealed") {
+		t.Fatalf("expected failure quoting stderr, got %v", err)
+	}
+
+	opts = helper("sleep")
+	opts.Decoder = cfg
+	opts.Timeout = 100 * time.Millisecond
+	if _, err := New(opts).Load(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	opts = helper("flood")
+	opts.Decoder = cfg
+	opts.MaxOutput = 1024
+	if _, err := New(opts).Load(context.Background()); err == nil || !strings.Contains(err.Error(), "exceeds 1024 bytes") {
+		t.Fatalf("expected output limit error, got %v", err)
+	}
+
+	opts = helper("orphan")
+	opts.Decoder = cfg
+	opts.Timeout = 100 * time.Millisecond
+	start := time.Now()
+	if _, err := New(opts).Load(context.Background()); err == nil {
+		t.Fatalf("expected orphaned output to fail the load")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the run to stop waiting for orphaned output, took %s", elapsed)
+	}
+}
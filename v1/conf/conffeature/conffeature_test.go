@@ -0,0 +1,56 @@
+package conffeature
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+func TestFlags(t *testing.T) {
+	cfg := conf.New()
+	cfg.MergeConfigMap(map[string]any{
+		"features": map[string]any{
+			"new_ui":      true,
+			"old_ui":      "off",
+			"dark_mode":   "yes",
+			"half":        50,
+			"beta_search": "25%",
+			"checkout_v2": map[string]any{"enabled": false, "rollout": "100%"},
+		},
+	})
+	flags := New(cfg, "features")
+
+	if !flags.IsEnabled("new_ui") || flags.IsEnabled("old_ui") || flags.IsEnabled("missing") {
+		t.Fatalf("unexpected boolean flag evaluation")
+	}
+	if !flags.IsEnabled("dark_mode") || flags.IsEnabled("half") {
+		t.Fatalf("expected yes to enable and 50 to be a partial rollout")
+	}
+	if flags.IsEnabled("beta_search") {
+		t.Fatalf("expected partial rollout not to be fully enabled")
+	}
+	if flags.IsEnabledFor("checkout_v2", "user-1") {
+		t.Fatalf("expected disabled flag to stay off regardless of rollout")
+	}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		id := fmt.Sprintf("user-%d", i)
+		first := flags.IsEnabledFor("beta_search", id)
+		if first != flags.IsEnabledFor("beta_search", id) {
+			t.Fatalf("expected stable rollout decision for %s", id)
+		}
+		if first {
+			enabled++
+		}
+	}
+	if enabled < 200 || enabled > 300 {
+		t.Fatalf("expected roughly 25%% of ids to be enabled, got %d", enabled)
+	}
+
+	cfg.MergeConfigMap(map[string]any{"features": map[string]any{"beta_search": true}})
+	if !flags.IsEnabled("beta_search") {
+		t.Fatalf("expected flags to follow configuration updates")
+	}
+}
@@ -1,16 +1,17 @@
 package conf
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -18,61 +19,151 @@ import (
 )
 
 // Config provides configuration handling similar to Viper.
+//
+// Writers serialize on mu and publish an immutable state after every change,
+// while getters read the latest published state without taking any lock.
+// Changes to the values, override and provider layers also serialize on
+// commitMu, which lets them release mu while the validators run.
 type Config struct {
-	mu          sync.RWMutex
-	defaults    map[string]any
-	values      map[string]any
-	envPrefix   string
-	envBindings map[string]string
-	cfgName     string
-	cfgType     string
-	cfgPaths    []string
-	file        string
-	automatic   bool
-	watcher     *fsnotify.Watcher
-	onChange    func()
-	watcherDone chan struct{}
-	loaders     map[string]Loader
-}
-
-// New creates a new Config instance.
-func New() *Config {
+	mu             sync.RWMutex
+	commitMu       sync.Mutex
+	current        atomic.Pointer[state]
+	defaults       map[string]any
+	defaultFuncs   map[string]func(*Config) any
+	values         map[string]any
+	overrides      map[string]any
+	envPrefix      string
+	envBindings    map[string]string
+	envFallback    []string
+	envBare        []string
+	cfgName        string
+	cfgType        string
+	cfgPaths       []string
+	file           string
+	fileUsed       string
+	searched       []string
+	fallbacks      []string
+	backups        int
+	fileLocking    bool
+	permCheck      PermissionCheck
+	verifier       Verifier
+	sigSuffix      string
+	platforms      bool
+	automatic      bool
+	structuredEnv  bool
+	locked         []string
+	locks          lockGuard
+	pinPatterns    []string
+	pins           pinSet
+	precedence     []Source
+	interceptors   []Interceptor
+	transforms     []transform
+	vars           []varBinding
+	faults         map[string]*fault
+	faultInjection bool
+	varsMu         sync.RWMutex
+	watcher        *fsnotify.Watcher
+	onChange       func()
+	watcherDone    chan struct{}
+	watchKick      chan struct{}
+	watchCtx       context.Context
+	stopSchedule   func()
+	loaders        map[string]Loader
+	encoders       map[string]Encoder
+	aliases        map[string]string
+	keyAliases     map[string]string
+	mimeTypes      map[string]string
+	providers      []providerEntry
+	providerValues map[string]any
+	raisedValues   map[string]any
+	configWeight   int
+	encrypted      map[string]Cipher
+	required       []string
+	meta           map[string]Meta
+	comments       map[string]string
+	secrets        []string
+	logger         Logger
+	events         *EventBus
+	hooks          []Hooks
+	history        *changeHistory
+	prevValues     map[string]any
+	prevOverrides  map[string]any
+	hasPrev        bool
+	staged         *stagedApply
+	validators     []func(Snapshot) error
+	coercion       CoercionPolicy
+	coerceMu       sync.Mutex
+	coerceWarns    map[string]error
+	regexps        regexpCache
+	frozen         bool
+	keyDelim       string
+	foldKeys       bool
+	parseOptions
+}
+
+// New creates a new Config instance configured by opts, see Option. It
+// panics when an option is invalid; NewE returns the error instead.
+func New(opts ...Option) *Config {
+	c, err := NewE(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// newConfig returns a Config with the default settings.
+func newConfig() *Config {
 	c := &Config{
-		defaults:    make(map[string]any),
-		values:      make(map[string]any),
-		envBindings: make(map[string]string),
-		cfgPaths:    []string{"."},
+		defaults:     make(map[string]any),
+		values:       make(map[string]any),
+		envBindings:  make(map[string]string),
+		cfgPaths:     []string{"."},
+		history:      newChangeHistory(defaultHistorySize),
+		events:       &EventBus{},
+		configWeight: DefaultConfigWeight,
 	}
 	c.loaders = defaultLoaders()
+	c.encoders = defaultEncoders()
+	c.mimeTypes = defaultMIMETypes()
+	c.publishLocked()
 	return c
 }
 
-// SetEnvPrefix sets a prefix for environment variables.
+// SetEnvPrefix sets a prefix for environment variables, dropping the
+// fallback prefixes set with SetEnvPrefixes.
 func (c *Config) SetEnvPrefix(prefix string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.envPrefix = prefix
+	c.mustNotBeFrozenLocked()
+	c.envPrefix, c.envFallback = prefix, nil
+	c.publishLocked()
 }
 
 // AutomaticEnv enables automatic environment variable lookup.
 func (c *Config) AutomaticEnv() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
 	c.automatic = true
+	c.publishLocked()
 }
 
 // BindEnv binds a configuration key to a specific environment variable.
 func (c *Config) BindEnv(key, env string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
 	c.envBindings[key] = env
+	c.publishLocked()
 }
 
 // SetDefault sets a default value for a key.
 func (c *Config) SetDefault(key string, value any) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
 	c.defaults[key] = value
+	c.publishLocked()
 }
 
 // SetConfigName defines the base name of the config file.
@@ -103,17 +194,40 @@ func (c *Config) SetConfigFile(file string) {
 	c.file = file
 }
 
-// ReadInConfig reads the configuration file and merges values.
+// ReadInConfig reads the configuration file and merges values. When the file
+// is missing or cannot be decoded, the fallbacks set with
+// SetFallbackConfigFile are tried in order.
 func (c *Config) ReadInConfig() error {
+	done := c.hookLoad(HookSourceFile)
+	c.commitMu.Lock()
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.readInConfigLocked()
+	var fallback *FallbackError
+	err := c.checkFrozenLocked()
+	if err == nil {
+		err = c.takeFaultLocked(HookSourceFile)
+	}
+	if err == nil {
+		fallback, err = c.readInConfigLocked()
+	}
+	c.mu.Unlock()
+	c.commitMu.Unlock()
+	if fallback != nil {
+		c.log().Warn("conf: loaded fallback config file", "file", fallback.File, "fallback", fallback.Fallback, "error", fallback.Err)
+		c.reportError(fallback)
+	}
+	done(err)
+	return err
 }
 
 // ReadConfig reads configuration data from the provided reader and merges it.
 func (c *Config) ReadConfig(r io.Reader) error {
+	c.commitMu.Lock()
+	defer c.commitMu.Unlock()
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
+	}
 	if c.cfgType == "" {
 		return errors.New("config type not set")
 	}
@@ -125,11 +239,11 @@ func (c *Config) ReadConfig(r io.Reader) error {
 	if err != nil {
 		return err
 	}
-	c.mergeConfigMapLocked(parsed)
-	return nil
+	return c.mergeConfigMapLocked(parsed)
 }
 
-// MergeConfigMap merges the provided map into the current configuration.
+// MergeConfigMap merges the provided map into the current configuration and
+// emits the resulting change events with ChangeSourceRuntime.
 func (c *Config) MergeConfigMap(data map[string]any) {
 	if data == nil {
 		return
@@ -138,59 +252,129 @@ func (c *Config) MergeConfigMap(data map[string]any) {
 	if normalized == nil {
 		return
 	}
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.mergeConfigMapLocked(normalized)
+	err := c.update(ChangeSourceRuntime, func() error {
+		return c.mergeConfigMapLocked(c.stripLockedLocked(normalized, ChangeSourceRuntime))
+	})
+	if err != nil {
+		if errors.Is(err, ErrFrozen) {
+			panic(err)
+		}
+		c.log().Error("conf: failed to merge config map", "error", err)
+	}
 }
 
-func (c *Config) readInConfigLocked() error {
-	if c.file == "" {
-		if c.cfgName == "" {
-			return nil
-		}
-		for _, p := range c.cfgPaths {
-			name := filepath.Join(p, c.cfgName)
-			if c.cfgType != "" {
-				name += "." + c.cfgType
-			}
-			if _, err := os.Stat(name); err == nil {
-				c.file = name
-				break
+func (c *Config) readInConfigLocked() (*FallbackError, error) {
+	file, searched, err := c.searchConfigFileLocked()
+	c.searched = searched
+	if err == nil && file == "" {
+		return nil, nil
+	}
+	if err == nil {
+		c.file = file
+		var parsed map[string]any
+		if parsed, err = c.readConfigFileLocked(file); err == nil {
+			if err = c.setValuesLocked(parsed); err == nil {
+				c.fileUsed = file
 			}
+			return nil, err
 		}
-		if c.file == "" {
-			return os.ErrNotExist
+	}
+	return c.readFallbackLocked(file, err)
+}
+
+// findConfigFileLocked returns the explicitly set config file or the first
+// candidate found in the search paths. An empty name without error means no
+// config file was configured.
+func (c *Config) findConfigFileLocked() (string, error) {
+	file, _, err := c.searchConfigFileLocked()
+	return file, err
+}
+
+// searchConfigFileLocked is findConfigFileLocked also returning the
+// candidates it checked.
+func (c *Config) searchConfigFileLocked() (string, []string, error) {
+	if c.file != "" {
+		return c.file, []string{c.file}, nil
+	}
+	if c.cfgName == "" {
+		return "", nil, nil
+	}
+	var searched []string
+	for _, name := range c.fileCandidatesLocked() {
+		searched = append(searched, name)
+		if fileExists(name) {
+			return name, searched, nil
 		}
 	}
+	return "", searched, fmt.Errorf("conf: config file %q not found in %s: %w", c.cfgName, strings.Join(c.cfgPaths, ", "), os.ErrNotExist)
+}
 
-	data, err := os.ReadFile(c.file)
+// readConfigFileLocked reads and decodes file using the loader registered for
+// its extension, after checking its signature, then applies the permission
+// check.
+func (c *Config) readConfigFileLocked(file string) (map[string]any, error) {
+	data, err := c.readFileLocked(file)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	parsed, err := c.decodeConfig(data, strings.TrimPrefix(strings.ToLower(filepath.Ext(c.file)), "."))
+	if err := c.verifyFileLocked(file, data); err != nil {
+		return nil, err
+	}
+	parsed, err := c.decodeConfig(data, strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), "."))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	c.values = make(map[string]any)
-	c.mergeConfigMapLocked(parsed)
-	return nil
+	if parsed == nil {
+		parsed = make(map[string]any)
+	}
+	if err := c.checkPermissionsLocked(file, parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
 }
 
-func (c *Config) mergeConfigMapLocked(data map[string]any) {
+func (c *Config) mergeConfigMapLocked(data map[string]any) error {
 	if data == nil {
-		return
+		return nil
 	}
-	if c.values == nil {
-		c.values = data
-		return
+	return c.setValuesLocked(mergeMaps(cloneMap(c.values), data))
+}
+
+// setValuesLocked runs the validators against the candidate values and, when
+// they pass, replaces the values layer, remembering the previous layers for
+// RollbackLast, and publishes the result. The caller must hold c.commitMu and
+// c.mu, and must not modify the previous values map in place.
+func (c *Config) setValuesLocked(values map[string]any) error {
+	return c.setLayersLocked(values, c.overrides)
+}
+
+// setLayersLocked is setValuesLocked replacing the override layer as well.
+func (c *Config) setLayersLocked(values, overrides map[string]any) error {
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
 	}
-	mergeMaps(c.values, data)
+	if err := c.runValidatorsLocked(values, overrides, c.providerValues, c.raisedValues); err != nil {
+		return err
+	}
+	// c.mu was released while the validators ran.
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
+	}
+	c.prevValues = c.values
+	c.prevOverrides = c.overrides
+	c.hasPrev = true
+	c.values = values
+	c.overrides = overrides
+	c.publishLocked()
+	return nil
 }
 
 // RegisterLoader registers or replaces the loader responsible for the provided extension.
+// Loaders can be registered at any time; when the watched config file uses
+// the extension, the watcher reloads it with the new loader.
 // The extension can optionally include a leading dot and is normalized to lower case.
 func (c *Config) RegisterLoader(ext string, loader Loader) {
-	normalized := strings.ToLower(strings.TrimPrefix(ext, "."))
+	normalized := normalizeExt(ext)
 	if normalized == "" || loader == nil {
 		return
 	}
@@ -200,22 +384,35 @@ func (c *Config) RegisterLoader(ext string, loader Loader) {
 		c.loaders = make(map[string]Loader)
 	}
 	c.loaders[normalized] = loader
+	if c.watchKick != nil && c.formatLocked(filepath.Ext(c.file)) == normalized {
+		select {
+		case c.watchKick <- struct{}{}:
+		default:
+		}
+	}
 }
 
 func (c *Config) decodeConfig(data []byte, format string) (map[string]any, error) {
-	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	format = c.formatLocked(format)
 	if format == "" {
-		return nil, errors.New("unsupported config file type")
+		return nil, ErrUnsupportedFormat
 	}
 	loader, ok := c.loaders[format]
+	if !ok {
+		loader, ok = globalLoader(format)
+	}
 	if !ok || loader == nil {
-		return nil, errors.New("unsupported config file type")
+		return nil, ErrUnsupportedFormat
 	}
 	values, err := loader.Load(data)
 	if err != nil {
 		return nil, err
 	}
-	return normalizeLoadedMap(values), nil
+	values = c.applyPlatformsLocked(normalizeLoadedMap(values))
+	if err := c.decryptSectionsLocked(values); err != nil {
+		return nil, err
+	}
+	return values, nil
 }
 
 func cloneMap(src map[string]any) map[string]any {
@@ -280,41 +477,16 @@ func mergeMaps(dst, src map[string]any) map[string]any {
 	return dst
 }
 
-func (c *Config) getEnv(key string) (string, bool) {
-	env, ok := c.envBindings[key]
-	if !ok {
-		env = strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
-		if c.envPrefix != "" {
-			env = c.envPrefix + "_" + env
-		}
-	}
-	val, exists := os.LookupEnv(env)
-	return val, exists
-}
-
-func (c *Config) get(key string) (any, bool) {
-	if c.automatic {
-		if v, ok := c.getEnv(key); ok {
-			return v, true
-		}
-	}
-	if v, ok := fetchValue(c.values, key); ok {
-		return v, true
-	}
-	if v, ok := c.getEnv(key); ok {
-		return v, true
-	}
-	return fetchValue(c.defaults, key)
-}
-
-func fetchValue(data map[string]any, key string) (any, bool) {
+// fetchValue returns the value of key in data, traversing nested maps and
+// lists at every sep.
+func fetchValue(data map[string]any, key, sep string) (any, bool) {
 	if data == nil {
 		return nil, false
 	}
 	if v, ok := data[key]; ok {
 		return v, true
 	}
-	parts := strings.Split(key, ".")
+	parts := strings.Split(key, sep)
 	var current any = data
 	for _, part := range parts {
 		switch node := current.(type) {
@@ -432,6 +604,7 @@ func (c *Config) WatchConfig() error {
 		return err
 	}
 	done := make(chan struct{})
+	kick := make(chan struct{}, 1)
 
 	c.mu.Lock()
 	if c.file == "" {
@@ -446,11 +619,15 @@ func (c *Config) WatchConfig() error {
 	}
 	c.watcher = w
 	c.watcherDone = done
+	c.watchKick = kick
 	file = c.file
 	c.mu.Unlock()
 
+	c.runHooks(func(h Hooks) { h.OnWatchStart(file) })
 	go func(watcher *fsnotify.Watcher) {
 		defer close(done)
+		defer c.runHooks(func(h Hooks) { h.OnWatchStop(file) })
+		var pending <-chan time.Time
 		for {
 			select {
 			case ev, ok := <-watcher.Events:
@@ -458,23 +635,26 @@ func (c *Config) WatchConfig() error {
 					return
 				}
 				if ev.Op&fsnotify.Write == fsnotify.Write {
-					if err := c.ReadInConfig(); err != nil {
-						log.Printf("conf: failed to reload config: %v", err)
-						continue
-					}
-					c.mu.RLock()
-					callback := c.onChange
-					c.mu.RUnlock()
-					if callback != nil {
-						callback()
-					}
+					pending = time.After(reloadDebounce)
+				}
+			case <-kick:
+				pending = time.After(reloadDebounce)
+			case <-pending:
+				pending = nil
+				before := c.load()
+				if err := c.ReadInConfig(); err != nil {
+					c.log().Error("conf: failed to reload config", "error", err)
+					c.reportError(err)
+					continue
 				}
+				c.notifyReload(before)
 			case err, ok := <-watcher.Errors:
 				if !ok {
 					return
 				}
 				if err != nil {
-					log.Printf("conf: watcher error: %v", err)
+					c.log().Error("conf: watcher error", "error", err)
+					c.reportError(err)
 				}
 			}
 		}
@@ -483,9 +663,13 @@ func (c *Config) WatchConfig() error {
 	return w.Add(file)
 }
 
-// Close releases resources associated with the watcher and resets its state.
+// Close stops the file watcher and the reload schedule and resets their state.
 func (c *Config) Close() error {
 	c.mu.Lock()
+	if c.stopSchedule != nil {
+		c.stopSchedule()
+		c.stopSchedule = nil
+	}
 	if c.watcher == nil {
 		c.mu.Unlock()
 		return nil
@@ -494,6 +678,7 @@ func (c *Config) Close() error {
 	done := c.watcherDone
 	c.watcher = nil
 	c.watcherDone = nil
+	c.watchKick = nil
 	c.mu.Unlock()
 	err := w.Close()
 	if done != nil {
@@ -504,244 +689,120 @@ func (c *Config) Close() error {
 
 // GetString returns a string value for the key.
 func (c *Config) GetString(key string) string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case string:
-			return val
-		default:
-			return stringify(val)
-		}
-	}
-	return ""
+	v, _ := c.GetStringE(key)
+	return v
 }
 
 // GetInt returns an int value for the key.
 func (c *Config) GetInt(key string) int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case int:
-			return val
-		case int64:
-			return int(val)
-		case float64:
-			return int(val)
-		case string:
-			i, _ := strconv.Atoi(val)
-			return i
-		}
-	}
-	return 0
+	i, err := c.GetIntE(key)
+	c.noteCoercion(err)
+	return i
 }
 
 // GetBool returns a boolean value for the key.
 func (c *Config) GetBool(key string) bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case bool:
-			return val
-		case string:
-			b, _ := strconv.ParseBool(val)
-			return b
-		case int:
-			return val != 0
-		case float64:
-			return val != 0
-		}
-	}
-	return false
+	b, err := c.GetBoolE(key)
+	c.noteCoercion(err)
+	return b
 }
 
 // GetFloat64 returns a float64 value for the key. When the stored value is not
 // compatible with a floating point representation, it falls back to 0.
 func (c *Config) GetFloat64(key string) float64 {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case float64:
-			return val
-		case float32:
-			return float64(val)
-		case int:
-			return float64(val)
-		case int64:
-			return float64(val)
-		case json.Number:
-			f, _ := val.Float64()
-			return f
-		case string:
-			f, _ := strconv.ParseFloat(val, 64)
-			return f
-		}
-	}
-	return 0
+	f, err := c.GetFloat64E(key)
+	c.noteCoercion(err)
+	return f
 }
 
 // GetDuration returns a time.Duration value for the key. Strings are parsed
 // using time.ParseDuration, numeric values are treated as nanoseconds, and
 // incompatible values yield 0.
 func (c *Config) GetDuration(key string) time.Duration {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case time.Duration:
-			return val
-		case int:
-			return time.Duration(val)
-		case int64:
-			return time.Duration(val)
-		case float64:
-			return time.Duration(val)
-		case string:
-			d, err := time.ParseDuration(val)
-			if err == nil {
-				return d
-			}
-		}
-	}
-	return 0
+	d, err := c.GetDurationE(key)
+	c.noteCoercion(err)
+	return d
 }
 
 // GetStringSlice returns a []string value for the key. Non compatible values
 // result in an empty slice.
 func (c *Config) GetStringSlice(key string) []string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		if res := toStringSlice(v); res != nil {
-			return res
-		}
+	res, err := c.GetStringSliceE(key)
+	c.noteCoercion(err)
+	if res == nil {
+		return []string{}
 	}
-	return []string{}
+	return res
 }
 
 // GetIntSlice returns a []int value for the key. Non convertible values result
 // in an empty slice.
 func (c *Config) GetIntSlice(key string) []int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch slice := v.(type) {
-		case []int:
-			return append([]int(nil), slice...)
-		case []any:
-			result := make([]int, 0, len(slice))
-			for _, item := range slice {
-				switch val := item.(type) {
-				case int:
-					result = append(result, val)
-				case int64:
-					result = append(result, int(val))
-				case float64:
-					result = append(result, int(val))
-				case string:
-					i, err := strconv.Atoi(val)
-					if err != nil {
-						return []int{}
-					}
-					result = append(result, i)
-				default:
-					return []int{}
-				}
-			}
-			return result
-		}
+	res, err := c.GetIntSliceE(key)
+	c.noteCoercion(err)
+	if res == nil {
+		return []int{}
 	}
-	return []int{}
+	return res
 }
 
 // GetStringMap returns a map[string]any value for the key. When the value is
 // not a compatible map, it returns an empty map.
 func (c *Config) GetStringMap(key string) map[string]any {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case map[string]any:
-			return cloneMap(val)
-		case map[string]string:
-			res := make(map[string]any, len(val))
-			for k, item := range val {
-				res[k] = item
-			}
-			return res
-		case map[any]any:
-			res := make(map[string]any, len(val))
-			for k, item := range val {
-				res[fmt.Sprint(k)] = item
-			}
-			return res
-		}
+	res, err := c.GetStringMapE(key)
+	c.noteCoercion(err)
+	if err != nil {
+		return map[string]any{}
 	}
-	return map[string]any{}
+	return res
 }
 
 // GetStringMapString returns a map[string]string value for the key. On
 // incompatible types, it returns an empty map.
 func (c *Config) GetStringMapString(key string) map[string]string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case map[string]string:
-			copy := make(map[string]string, len(val))
-			for k, item := range val {
-				copy[k] = item
-			}
-			return copy
-		case map[string]any:
-			res := make(map[string]string, len(val))
-			for k, item := range val {
-				res[k] = stringify(item)
-			}
-			return res
-		case map[any]any:
-			res := make(map[string]string, len(val))
-			for k, item := range val {
-				res[fmt.Sprint(k)] = stringify(item)
-			}
-			return res
-		}
+	res, err := c.GetStringMapStringE(key)
+	c.noteCoercion(err)
+	if err != nil {
+		return map[string]string{}
 	}
-	return map[string]string{}
+	return res
 }
 
 // GetStringMapStringSlice returns a map[string][]string for the key. When the
 // value cannot be converted, an empty map is returned.
 func (c *Config) GetStringMapStringSlice(key string) map[string][]string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if v, ok := c.get(key); ok {
-		switch val := v.(type) {
-		case map[string][]string:
-			res := make(map[string][]string, len(val))
-			for k, item := range val {
-				copy := append([]string(nil), item...)
-				res[k] = copy
-			}
-			return res
-		case map[string]any:
-			res := make(map[string][]string, len(val))
-			for k, item := range val {
-				res[k] = toStringSlice(item)
-			}
-			return res
-		case map[any]any:
-			res := make(map[string][]string, len(val))
-			for k, item := range val {
-				res[fmt.Sprint(k)] = toStringSlice(item)
-			}
-			return res
+	res, err := c.GetStringMapStringSliceE(key)
+	c.noteCoercion(err)
+	if err != nil {
+		return map[string][]string{}
+	}
+	return res
+}
+
+// GetMany resolves all the provided keys against the same configuration
+// snapshot. Keys that cannot be resolved are omitted from the result.
+func (c *Config) GetMany(keys ...string) map[string]any {
+	s := c.load()
+	res := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if v, ok := s.read(key); ok {
+			res[key] = cloneValue(v)
+		}
+	}
+	return res
+}
+
+// GetManyStrings is like GetMany but converts every resolved value to a
+// string, as GetString does.
+func (c *Config) GetManyStrings(keys ...string) map[string]string {
+	s := c.load()
+	res := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if v, ok := s.read(key); ok {
+			res[key] = stringify(v)
 		}
 	}
-	return map[string][]string{}
+	return res
 }
 
 // Unmarshal decodes the configuration at the provided key into the given
@@ -754,27 +815,34 @@ func (c *Config) Unmarshal(key string, out any) error {
 		data any
 		ok   bool
 	)
-	c.mu.RLock()
+	s := c.load()
 	if key == "" {
-		if c.values != nil {
-			data = cloneMap(c.values)
+		if s.values != nil || s.overrides != nil {
+			data = s.canonicalMap(s.overlay("", s.values))
 			ok = true
 		}
 	} else {
-		if v, exists := c.get(key); exists {
+		if v, exists := s.read(key); exists {
 			data = cloneValue(v)
 			ok = true
 		}
 	}
-	c.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("conf: key %q not found", key)
 	}
+	return decode(data, out)
+}
+
+// decode projects data onto out using mapstructure with weak typing. The
+// values are merged into what out already holds, except for explicit nulls,
+// which reset the matching fields so pointer fields end up nil.
+func decode(data, out any) error {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		TagName:          "mapstructure",
 		Result:           out,
 		WeaklyTypedInput: true,
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			resetNullFields,
 			mapstructure.StringToTimeDurationHookFunc(),
 		),
 	})
@@ -784,37 +852,43 @@ func (c *Config) Unmarshal(key string, out any) error {
 	return decoder.Decode(data)
 }
 
-func stringify(v any) string {
-	switch t := v.(type) {
-	case string:
-		return t
-	case fmt.Stringer:
-		return t.String()
-	default:
-		return fmt.Sprintf("%v", v)
+// resetNullFields is a decode hook zeroing the fields of the struct being
+// decoded whose key holds an explicit null, which mapstructure otherwise
+// skips.
+func resetNullFields(from, to reflect.Value) (any, error) {
+	m, ok := from.Interface().(map[string]any)
+	if !ok || to.Kind() != reflect.Struct || !to.CanSet() {
+		return from.Interface(), nil
 	}
+	for key, v := range m {
+		if v != nil {
+			continue
+		}
+		if field := structField(to, key); field.IsValid() && field.CanSet() {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+	return m, nil
 }
 
-func toStringSlice(v any) []string {
-	switch val := v.(type) {
-	case []string:
-		return append([]string(nil), val...)
-	case []any:
-		result := make([]string, 0, len(val))
-		for _, item := range val {
-			result = append(result, stringify(item))
+// structField returns the field of v decoded from key, matched the way
+// mapstructure does: by tag, else by name, ignoring case.
+func structField(v reflect.Value, key string) reflect.Value {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
 		}
-		return result
-	case string:
-		if val == "" {
-			return []string{}
+		name := f.Name
+		if tag, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
 		}
-		parts := strings.Split(val, ",")
-		for i, part := range parts {
-			parts[i] = strings.TrimSpace(part)
+		if strings.EqualFold(name, key) {
+			return v.Field(i)
 		}
-		return parts
-	default:
-		return nil
 	}
+	return reflect.Value{}
 }
@@ -228,6 +228,54 @@ func TestRegisterCustomLoader(t *testing.T) {
 	}
 }
 
+func TestRegisterGlobalLoader(t *testing.T) {
+	existing := New()
+	RegisterGlobalLoader(".GlobalFake", fakeLoader{})
+	existing.SetConfigType("globalfake")
+	if err := existing.ReadConfig(strings.NewReader("global\n")); err != nil {
+		t.Fatalf("expected existing config to use the global loader, got %v", err)
+	}
+	if got := existing.GetString("raw"); got != "global" {
+		t.Fatalf("expected raw from the global loader, got %q", got)
+	}
+
+	c := New()
+	c.RegisterLoader("globalfake", JSONLoader{})
+	c.SetConfigType("globalfake")
+	if err := c.ReadConfig(strings.NewReader(`{"raw": "local"}`)); err != nil || c.GetString("raw") != "local" {
+		t.Fatalf("expected the config loader to take precedence, got %v", err)
+	}
+}
+
+func TestAliasExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.conf")
+	if err := os.WriteFile(path, []byte("port = 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	if err := c.ReadInConfig(); err == nil {
+		t.Fatalf("expected .conf to be unsupported without an alias")
+	}
+	c.AliasExtension(".CONF", "ini")
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read aliased extension: %v", err)
+	}
+	if got := c.GetInt("port"); got != 8080 {
+		t.Fatalf("expected port to be 8080, got %d", got)
+	}
+
+	c.RegisterLoader("ini", fakeLoader{})
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("raw"); got != "port = 8080" {
+		t.Fatalf("expected alias to follow the replaced loader, got %q", got)
+	}
+}
+
 func TestWatchConfigSingleTrigger(t *testing.T) {
 	tmp, err := os.CreateTemp("", "cfg*.yaml")
 	if err != nil {
@@ -273,6 +321,41 @@ func TestWatchConfigSingleTrigger(t *testing.T) {
 	}
 }
 
+func TestWatchConfigCoalescesWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("value: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	var calls int32
+	c.OnConfigChange(func() { atomic.AddInt32(&calls, 1) })
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	f.WriteString("value: 2\n")
+	f.Close()
+	time.Sleep(4 * reloadDebounce)
+
+	if got := c.GetInt("value"); got != 2 {
+		t.Fatalf("expected the written value, got %d", got)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected a single reload for the burst, got %d", got)
+	}
+}
+
 func TestWatchConfigHandlesErrors(t *testing.T) {
 	tmp, err := os.CreateTemp("", "cfg*.yaml")
 	if err != nil {
@@ -661,3 +744,43 @@ func TestUnmarshal(t *testing.T) {
 		t.Fatalf("expected error for missing key")
 	}
 }
+
+func TestUnmarshalMergesIntoOut(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"tags": map[string]any{"b": 2}, "port": 80})
+
+	out := struct {
+		Tags map[string]int `mapstructure:"tags"`
+		Port int            `mapstructure:"port"`
+	}{Tags: map[string]int{"a": 1}}
+	if err := c.Unmarshal("", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(out); got != "{map[a:1 b:2] 80}" {
+		t.Fatalf("expected the map to be merged into, got %s", got)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	c := New()
+	c.SetDefault("log_level", "info")
+	c.MergeConfigMap(map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 8080},
+	})
+
+	got := c.GetMany("server.host", "server.port", "log_level", "missing")
+	if len(got) != 3 {
+		t.Fatalf("expected 3 resolved keys, got %#v", got)
+	}
+	if got["server.port"] != 8080 || got["log_level"] != "info" {
+		t.Fatalf("unexpected values %#v", got)
+	}
+	if _, ok := got["missing"]; ok {
+		t.Fatalf("expected missing key to be omitted")
+	}
+
+	strs := c.GetManyStrings("server.port", "server.host")
+	if strs["server.port"] != "8080" || strs["server.host"] != "localhost" {
+		t.Fatalf("unexpected string values %#v", strs)
+	}
+}
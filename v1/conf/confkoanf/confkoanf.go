@@ -0,0 +1,129 @@
+// Package confkoanf adapts koanf (github.com/knadh/koanf) parsers and
+// providers to this package and back, so the koanf ecosystem can be reused
+// while keeping the conf API.
+//
+// Format turns a koanf parser into a conf.Loader and conf.Encoder, Parser
+// turns a conf loader and encoder into a koanf parser, and Provider makes a
+// koanf provider usable with Config.AddProvider:
+//
+//	cfg.RegisterLoader("hcl", confkoanf.Format{Parser: hcl.Parser(true)})
+//	cfg.AddProvider(confkoanf.NewProvider("s3", s3.Provider(s3cfg), yaml.Parser()))
+package confkoanf
+
+import (
+	"context"
+	"errors"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// ErrNoEncoder is returned by Parser.Marshal when the parser has no Encoder.
+var ErrNoEncoder = errors.New("confkoanf: no encoder set")
+
+// Format adapts a koanf parser to conf.Loader and conf.Encoder.
+type Format struct {
+	Parser koanf.Parser
+}
+
+// Load decodes data with the koanf parser.
+func (f Format) Load(data []byte) (map[string]any, error) {
+	return f.Parser.Unmarshal(data)
+}
+
+// Encode encodes values with the koanf parser.
+func (f Format) Encode(values map[string]any) ([]byte, error) {
+	return f.Parser.Marshal(values)
+}
+
+// Parser adapts a conf.Loader, and optionally a conf.Encoder, to
+// koanf.Parser.
+type Parser struct {
+	Loader  conf.Loader
+	Encoder conf.Encoder
+}
+
+// Unmarshal decodes data with the loader.
+func (p Parser) Unmarshal(data []byte) (map[string]any, error) {
+	return p.Loader.Load(data)
+}
+
+// Marshal encodes values with the encoder.
+func (p Parser) Marshal(values map[string]any) ([]byte, error) {
+	if p.Encoder == nil {
+		return nil, ErrNoEncoder
+	}
+	return p.Encoder.Encode(values)
+}
+
+// Provider adapts a koanf provider to conf.Provider.
+type Provider struct {
+	name     string
+	provider koanf.Provider
+	parser   koanf.Parser
+}
+
+// NewProvider returns a provider named name reading p. Providers returning
+// raw bytes, such as koanf's file or S3 providers, need parser to decode
+// them; parser is nil for providers returning maps, such as env or confmap.
+func NewProvider(name string, p koanf.Provider, parser koanf.Parser) *Provider {
+	return &Provider{name: name, provider: p, parser: parser}
+}
+
+// Name returns the name given to NewProvider.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Load reads the koanf provider, decoding its bytes with the parser when one
+// is set.
+func (p *Provider) Load(context.Context) (map[string]any, error) {
+	if p.parser == nil {
+		return p.provider.Read()
+	}
+	data, err := p.provider.ReadBytes()
+	if err != nil {
+		return nil, err
+	}
+	return p.parser.Unmarshal(data)
+}
+
+// koanfWatcher is implemented by koanf providers able to report changes,
+// such as the file provider.
+type koanfWatcher interface {
+	Watch(cb func(event any, err error)) error
+}
+
+// Watch forwards the change notifications of koanf providers implementing
+// Watch(func(event any, err error)) error, stopping at the first error. Other
+// providers never report a change, so Watch just waits for ctx to be done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	w, ok := p.provider.(koanfWatcher)
+	if !ok {
+		<-ctx.Done()
+		return nil
+	}
+	errc := make(chan error, 1)
+	err := w.Watch(func(_ any, err error) {
+		if err != nil {
+			select {
+			case errc <- err:
+			default:
+			}
+			return
+		}
+		notify()
+	})
+	if err != nil {
+		return err
+	}
+	if u, ok := p.provider.(interface{ Unwatch() error }); ok {
+		defer u.Unwatch()
+	}
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errc:
+		return err
+	}
+}
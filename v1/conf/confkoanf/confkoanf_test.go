@@ -0,0 +1,56 @@
+package confkoanf
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/v2"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+func TestAdapters(t *testing.T) {
+	c := conf.New()
+	c.RegisterLoader("kjson", Format{Parser: json.Parser()})
+	c.AddProvider(NewProvider("raw", rawbytes.Provider([]byte(`{"db": {"host": "koanf"}}`)), json.Parser()))
+	if err := c.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("db.host"); got != "koanf" {
+		t.Fatalf("expected value from the koanf provider, got %q", got)
+	}
+	values, err := c.Decode([]byte(`{"port": 8080}`), "kjson")
+	if err != nil || values["port"] != float64(8080) {
+		t.Fatalf("expected the koanf parser to decode, got %v, %v", values, err)
+	}
+
+	k := koanf.New(".")
+	parser := Parser{Loader: conf.YAMLLoader{}, Encoder: conf.JSONEncoder{}}
+	if err := k.Load(rawbytes.Provider([]byte("db:\n  port: 5432\n")), parser); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.Int("db.port"); got != 5432 {
+		t.Fatalf("expected koanf to use the conf loader, got %d", got)
+	}
+	if _, err := (Parser{Loader: conf.YAMLLoader{}}).Marshal(nil); err != ErrNoEncoder {
+		t.Fatalf("expected ErrNoEncoder, got %v", err)
+	}
+}
+
+func TestWatchUnwatchableProvider(t *testing.T) {
+	p := NewProvider("raw", rawbytes.Provider([]byte(`{}`)), json.Parser())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- p.Watch(ctx, func() {}) }()
+	select {
+	case err := <-done:
+		t.Fatalf("expected Watch to wait for the context, returned %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("expected no error once the context is done, got %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Package confmqtt provides a conf.Provider subscribed to an MQTT topic
+// carrying configuration documents, typically published as retained
+// messages so every device receives the current configuration as soon as it
+// subscribes:
+//
+//	mosquitto_pub -r -t devices/sensors/config -m '{"interval": "30s"}'
+//
+// Each message replaces the values provided so far. The provider implements
+// conf.ProviderWatcher, so conf.Config.WatchProviders applies new messages
+// as they arrive.
+package confmqtt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// Subscriber is the part of mqtt.Client used by the provider.
+type Subscriber interface {
+	Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token
+}
+
+// Options configures a Provider.
+type Options struct {
+	// Topic carries the configuration documents.
+	Topic string
+	// QoS is the quality of service of the subscription.
+	QoS byte
+	// Format is the format of the payloads, an extension or a MIME type.
+	// When empty, payloads starting with "{" are decoded as JSON and the
+	// others as YAML.
+	Format string
+	// Decoder decodes the payloads, usually the Config the provider is
+	// added to.
+	Decoder conf.Decoder
+	// Wait bounds how long the first Load waits for a retained message,
+	// 5 seconds unless set. Without one, the provider starts empty and later
+	// loads return at once.
+	Wait time.Duration
+}
+
+// Provider holds the values of the latest message received on the topic.
+type Provider struct {
+	client Subscriber
+	opts   Options
+
+	subMu      sync.Mutex
+	subscribed bool
+	first      sync.Once
+	received   chan struct{}
+
+	mu     sync.Mutex
+	values map[string]any
+	err    error
+	waited bool
+	notify func()
+}
+
+// New returns a provider subscribing to opts.Topic through client.
+func New(client Subscriber, opts Options) *Provider {
+	if opts.Wait <= 0 {
+		opts.Wait = 5 * time.Second
+	}
+	return &Provider{client: client, opts: opts, received: make(chan struct{})}
+}
+
+// Name identifies the topic.
+func (p *Provider) Name() string {
+	return "mqtt:" + p.opts.Topic
+}
+
+// Load returns the values of the latest message, subscribing on first use.
+// An error is returned when the latest message could not be decoded.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	if err := p.subscribe(); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	waited := p.waited
+	p.mu.Unlock()
+	if !waited {
+		timer := time.NewTimer(p.opts.Wait)
+		defer timer.Stop()
+		select {
+		case <-p.received:
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.waited = true
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.values, nil
+}
+
+// Watch calls notify after every message received until ctx is done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	if err := p.subscribe(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.notify = notify
+	p.mu.Unlock()
+	<-ctx.Done()
+	p.mu.Lock()
+	p.notify = nil
+	p.mu.Unlock()
+	return nil
+}
+
+// subscribe subscribes to the topic unless already subscribed. A failed
+// subscription is retried on the next call.
+func (p *Provider) subscribe() error {
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+	if p.subscribed {
+		return nil
+	}
+	token := p.client.Subscribe(p.opts.Topic, p.opts.QoS, p.handle)
+	token.Wait()
+	if err := token.Error(); err != nil {
+		return err
+	}
+	p.subscribed = true
+	return nil
+}
+
+func (p *Provider) handle(_ mqtt.Client, msg mqtt.Message) {
+	payload := msg.Payload()
+	if len(payload) == 0 {
+		return
+	}
+	var values map[string]any
+	var err error
+	if p.opts.Decoder == nil {
+		err = errors.New("no decoder set")
+	} else {
+		values, err = p.opts.Decoder.Decode(payload, p.format(payload))
+	}
+
+	p.mu.Lock()
+	if err == nil {
+		p.values = values
+	}
+	p.err = err
+	notify := p.notify
+	p.mu.Unlock()
+	p.first.Do(func() { close(p.received) })
+	if notify != nil {
+		notify()
+	}
+}
+
+func (p *Provider) format(payload []byte) string {
+	if p.opts.Format != "" {
+		return p.opts.Format
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(payload), []byte("{")) {
+		return "json"
+	}
+	return "yaml"
+}
@@ -0,0 +1,124 @@
+package confmqtt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+type doneToken struct{}
+
+func (doneToken) Wait() bool                     { return true }
+func (doneToken) WaitTimeout(time.Duration) bool { return true }
+func (doneToken) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+func (doneToken) Error() error { return nil }
+
+type failedToken struct{ doneToken }
+
+func (failedToken) Error() error { return errors.New("broker unavailable") }
+
+type message struct {
+	mqtt.Message
+	payload []byte
+}
+
+func (m message) Payload() []byte { return m.payload }
+
+// broker delivers the retained payload on subscription, like a real broker.
+type broker struct {
+	mu       sync.Mutex
+	retained []byte
+	handler  mqtt.MessageHandler
+	failures int
+}
+
+func (b *broker) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	b.mu.Lock()
+	if b.failures > 0 {
+		b.failures--
+		b.mu.Unlock()
+		return failedToken{}
+	}
+	b.handler = callback
+	retained := b.retained
+	b.mu.Unlock()
+	if retained != nil {
+		go callback(nil, message{payload: retained})
+	}
+	return doneToken{}
+}
+
+func (b *broker) publish(payload string) {
+	b.mu.Lock()
+	handler := b.handler
+	b.mu.Unlock()
+	handler(nil, message{payload: []byte(payload)})
+}
+
+func TestProviderRetainedAndUpdates(t *testing.T) {
+	b := &broker{retained: []byte(`{"interval": "30s", "sensors": ["a", "b"]}`)}
+	cfg := conf.New()
+	cfg.AddProvider(New(b, Options{Topic: "devices/config", Decoder: cfg}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.ReadProviders(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetDuration("interval") != 30*time.Second || len(cfg.GetStringSlice("sensors")) != 2 {
+		t.Fatalf("unexpected values %v", cfg.AllSettingsFlat())
+	}
+
+	changed := make(chan struct{}, 1)
+	cfg.Subscribe(conf.KeyPrefix("interval"), func(conf.ChangeEvent) { changed <- struct{}{} })
+	cfg.WatchProviders(ctx)
+	time.Sleep(50 * time.Millisecond)
+	b.publish("interval: 1m\n")
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a change event for the new payload")
+	}
+	if got := cfg.GetDuration("interval"); got != time.Minute {
+		t.Fatalf("expected YAML payload to be applied, got %v", got)
+	}
+
+	b.publish("{not json")
+	if err := cfg.ReadProviders(ctx); err == nil {
+		t.Fatalf("expected error for an invalid payload")
+	}
+	if got := cfg.GetDuration("interval"); got != time.Minute {
+		t.Fatalf("expected previous values to be kept, got %v", got)
+	}
+}
+
+func TestProviderRetriesSubscribe(t *testing.T) {
+	b := &broker{failures: 1}
+	p := New(b, Options{Topic: "devices/config", Decoder: conf.New(), Wait: 200 * time.Millisecond})
+	if _, err := p.Load(context.Background()); err == nil {
+		t.Fatalf("expected the failed subscription to be reported")
+	}
+
+	start := time.Now()
+	if _, err := p.Load(context.Background()); err != nil {
+		t.Fatalf("expected the subscription to be retried, got %v", err)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Fatalf("expected the first load to wait for a retained message")
+	}
+	start = time.Now()
+	if _, err := p.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("expected later loads not to wait again, took %s", elapsed)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/mirkobrombin/go-conf-builder/v1/conf/confmqtt
+
+go 1.23.4
+
+require (
+	github.com/eclipse/paho.mqtt.golang v1.5.0
+	github.com/mirkobrombin/go-conf-builder v0.1.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.5.0 // indirect
+	github.com/fsnotify/fsnotify v1.9.0 // indirect
+	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	golang.org/x/crypto v0.33.0 // indirect
+	golang.org/x/net v0.35.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/ini.v1 v1.67.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,82 @@
+// Package confnats provides a conf.Provider backed by a NATS JetStream
+// key-value bucket. Every key of the bucket is a configuration key, the key
+// delimiter of the Config, a dot by default, separating the levels, holding
+// the value as text:
+//
+//	nats kv put app server.port 8080
+//
+// The provider implements conf.ProviderWatcher, so conf.Config.WatchProviders
+// reloads the configuration as soon as the bucket changes.
+package confnats
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Provider reads the configuration from a key-value bucket.
+type Provider struct {
+	kv jetstream.KeyValue
+}
+
+// New returns a provider reading kv.
+func New(kv jetstream.KeyValue) *Provider {
+	return &Provider{kv: kv}
+}
+
+// Name identifies the bucket.
+func (p *Provider) Name() string {
+	return "nats:" + p.kv.Bucket()
+}
+
+// Load returns the current value of every key in the bucket.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	lister, err := p.kv.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lister.Stop()
+
+	sep := conf.KeyDelimiterFromContext(ctx)
+	values := make(map[string]any)
+	for key := range lister.Keys() {
+		entry, err := p.kv.Get(ctx, key)
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		conf.SetPath(values, key, sep, string(entry.Value()))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Watch calls notify after every put or delete in the bucket until ctx is
+// done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	w, err := p.kv.WatchAll(ctx, jetstream.UpdatesOnly())
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry, ok := <-w.Updates():
+			if !ok {
+				return nil
+			}
+			if entry != nil {
+				notify()
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package confnats
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type entry struct {
+	jetstream.KeyValueEntry
+	value string
+}
+
+func (e entry) Value() []byte { return []byte(e.value) }
+
+type lister struct {
+	keys chan string
+}
+
+func (l lister) Keys() <-chan string { return l.keys }
+func (l lister) Stop() error         { return nil }
+
+type watcher struct {
+	updates chan jetstream.KeyValueEntry
+}
+
+func (w watcher) Updates() <-chan jetstream.KeyValueEntry { return w.updates }
+func (w watcher) Stop() error                             { return nil }
+
+// memoryKV implements the part of jetstream.KeyValue used by the provider.
+type memoryKV struct {
+	jetstream.KeyValue
+	mu       sync.Mutex
+	values   map[string]string
+	watchers []watcher
+}
+
+func (kv *memoryKV) Bucket() string { return "app" }
+
+func (kv *memoryKV) ListKeys(ctx context.Context, opts ...jetstream.WatchOpt) (jetstream.KeyLister, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	l := lister{keys: make(chan string, len(kv.values))}
+	for key := range kv.values {
+		l.keys <- key
+	}
+	close(l.keys)
+	return l, nil
+}
+
+func (kv *memoryKV) Get(ctx context.Context, key string) (jetstream.KeyValueEntry, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	v, ok := kv.values[key]
+	if !ok {
+		return nil, jetstream.ErrKeyNotFound
+	}
+	return entry{value: v}, nil
+}
+
+func (kv *memoryKV) WatchAll(ctx context.Context, opts ...jetstream.WatchOpt) (jetstream.KeyWatcher, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	w := watcher{updates: make(chan jetstream.KeyValueEntry, 10)}
+	kv.watchers = append(kv.watchers, w)
+	return w, nil
+}
+
+func (kv *memoryKV) put(key, value string) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.values[key] = value
+	for _, w := range kv.watchers {
+		w.updates <- entry{value: value}
+	}
+}
+
+func TestProviderLoadAndWatch(t *testing.T) {
+	kv := &memoryKV{values: map[string]string{"server.port": "8080", "name": "demo"}}
+	cfg := conf.New()
+	cfg.AddProvider(New(kv))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.ReadProviders(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetInt("server.port") != 8080 || cfg.GetString("name") != "demo" {
+		t.Fatalf("unexpected values %v", cfg.AllSettingsFlat())
+	}
+
+	changed := make(chan conf.ChangeEvent, 1)
+	cfg.Subscribe(nil, func(ev conf.ChangeEvent) { changed <- ev })
+	cfg.WatchProviders(ctx)
+	for i := 0; ; i++ {
+		kv.mu.Lock()
+		watching := len(kv.watchers) > 0
+		kv.mu.Unlock()
+		if watching {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("expected provider to be watched")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	kv.put("server.port", "9090")
+
+	select {
+	case ev := <-changed:
+		if ev.Key != "server.port" || ev.Source != conf.ChangeSourceProvider {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected change event after a put")
+	}
+	if got := cfg.GetInt("server.port"); got != 9090 {
+		t.Fatalf("expected reloaded port, got %d", got)
+	}
+}
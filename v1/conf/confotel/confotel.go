@@ -0,0 +1,114 @@
+// Package confotel exports configuration subtrees as OpenTelemetry resource
+// attributes and baggage, so values such as service.name or
+// deployment.environment defined in config flow into telemetry.
+//
+// Nested keys are joined with dots relative to the selected subtree, so with
+//
+//	telemetry:
+//	  service:
+//	    name: checkout
+//
+// Attributes(cfg, "telemetry") yields service.name="checkout". Secret keys
+// are never exported.
+package confotel
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Attributes converts the subtree at key into attributes sorted by name.
+func Attributes(cfg *conf.Config, key string) []attribute.KeyValue {
+	flat := flatten(cfg, key)
+	names := make([]string, 0, len(flat))
+	for name := range flat {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	attrs := make([]attribute.KeyValue, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, toAttribute(name, flat[name]))
+	}
+	return attrs
+}
+
+// Resource returns a schemaless resource holding Attributes(cfg, key). It is
+// typically merged with resource.Default().
+func Resource(cfg *conf.Config, key string) *resource.Resource {
+	return resource.NewSchemaless(Attributes(cfg, key)...)
+}
+
+// Baggage converts the subtree at key into baggage members. Values are
+// converted to strings.
+func Baggage(cfg *conf.Config, key string) (baggage.Baggage, error) {
+	flat := flatten(cfg, key)
+	members := make([]baggage.Member, 0, len(flat))
+	for name, value := range flat {
+		m, err := baggage.NewMemberRaw(name, fmt.Sprint(value))
+		if err != nil {
+			return baggage.Baggage{}, fmt.Errorf("confotel: invalid baggage member %q: %w", name, err)
+		}
+		members = append(members, m)
+	}
+	return baggage.New(members...)
+}
+
+// flatten returns the leaves of the subtree at key, named by their path
+// joined with dots. Secrets are matched against the full key, joined with
+// the key delimiter of cfg.
+func flatten(cfg *conf.Config, key string) map[string]any {
+	sep := cfg.KeyDelimiter()
+	out := make(map[string]any)
+	var walk func(prefix, path string, m map[string]any)
+	walk = func(prefix, path string, m map[string]any) {
+		for k, v := range m {
+			name, full := k, k
+			if prefix != "" {
+				name = prefix + "." + k
+			}
+			if path != "" {
+				full = path + sep + k
+			}
+			if cfg.IsSecret(full) {
+				continue
+			}
+			if nested, ok := v.(map[string]any); ok {
+				walk(name, full, nested)
+				continue
+			}
+			out[name] = v
+		}
+	}
+	walk("", key, cfg.GetStringMap(key))
+	return out
+}
+
+func toAttribute(name string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(name, v)
+	case bool:
+		return attribute.Bool(name, v)
+	case int:
+		return attribute.Int(name, v)
+	case int64:
+		return attribute.Int64(name, v)
+	case float64:
+		return attribute.Float64(name, v)
+	case []string:
+		return attribute.StringSlice(name, v)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return attribute.StringSlice(name, items)
+	default:
+		return attribute.String(name, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,57 @@
+package confotel
+
+import (
+	"testing"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func newConfig() *conf.Config {
+	cfg := conf.New()
+	cfg.MergeConfigMap(map[string]any{
+		"telemetry": map[string]any{
+			"service":    map[string]any{"name": "checkout", "version": "1.2.0"},
+			"deployment": map[string]any{"environment": "prod"},
+			"sampled":    true,
+			"api_token":  "secret",
+		},
+	})
+	return cfg
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := Attributes(newConfig(), "telemetry")
+	want := []attribute.KeyValue{
+		attribute.String("deployment.environment", "prod"),
+		attribute.Bool("sampled", true),
+		attribute.String("service.name", "checkout"),
+		attribute.String("service.version", "1.2.0"),
+	}
+	if len(attrs) != len(want) {
+		t.Fatalf("expected %d attributes, got %v", len(want), attrs)
+	}
+	for i := range want {
+		if attrs[i] != want[i] {
+			t.Fatalf("expected %v at %d, got %v", want[i], i, attrs[i])
+		}
+	}
+
+	res := Resource(newConfig(), "telemetry")
+	if v, ok := res.Set().Value("service.name"); !ok || v.AsString() != "checkout" {
+		t.Fatalf("expected service.name in resource, got %v", res)
+	}
+}
+
+func TestBaggage(t *testing.T) {
+	b, err := Baggage(newConfig(), "telemetry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Member("deployment.environment").Value(); got != "prod" {
+		t.Fatalf("expected deployment.environment=prod, got %q", got)
+	}
+	if got := b.Member("api_token").Key(); got != "" {
+		t.Fatalf("expected secret key to be skipped")
+	}
+}
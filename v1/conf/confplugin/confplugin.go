@@ -0,0 +1,58 @@
+// Package confplugin loads config format loaders from Go plugins, so
+// proprietary formats can be shipped separately from the binaries reading
+// them.
+//
+// A plugin is a main package built with -buildmode=plugin. Its init
+// functions can call conf.RegisterGlobalLoader directly, or it can export a
+// Loaders variable of type map[string]conf.Loader, whose entries Open
+// registers:
+//
+//	package main
+//
+//	import "github.com/mirkobrombin/go-conf-builder/v1/conf"
+//
+//	var Loaders = map[string]conf.Loader{"acme": acmeLoader{}}
+//
+// Go plugins are only supported on some platforms and must be built with the
+// same toolchain and dependency versions as the host; Open reports an error
+// elsewhere. Importing this package is the opt-in: the core package does not
+// depend on plugin.
+package confplugin
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// LoadersSymbol is the name of the optional variable exported by a plugin.
+const LoadersSymbol = "Loaders"
+
+// Open loads the plugin at path, which runs its init functions, and
+// registers the loaders it exports as global loaders.
+func Open(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("confplugin: %w", err)
+	}
+	sym, err := p.Lookup(LoadersSymbol)
+	if err != nil {
+		// The plugin registers its loaders from init.
+		return nil
+	}
+	loaders, ok := sym.(*map[string]conf.Loader)
+	if !ok {
+		return fmt.Errorf("confplugin: %s: %s is %T, want map[string]conf.Loader", path, LoadersSymbol, sym)
+	}
+	Register(*loaders)
+	return nil
+}
+
+// Register registers every loader of loaders as a global loader, keyed by
+// extension.
+func Register(loaders map[string]conf.Loader) {
+	for ext, loader := range loaders {
+		conf.RegisterGlobalLoader(ext, loader)
+	}
+}
@@ -0,0 +1,30 @@
+package confplugin
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+type rawLoader struct{}
+
+func (rawLoader) Load(data []byte) (map[string]any, error) {
+	return map[string]any{"raw": string(data)}, nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(map[string]conf.Loader{".Plugtest": rawLoader{}})
+	c := conf.New()
+	c.SetConfigType("plugtest")
+	if err := c.ReadConfig(strings.NewReader("hello")); err != nil {
+		t.Fatalf("expected the global loader to be used, got %v", err)
+	}
+	if got := c.GetString("raw"); got != "hello" {
+		t.Fatalf("expected raw value, got %q", got)
+	}
+	if err := Open(filepath.Join(t.TempDir(), "missing.so")); err == nil {
+		t.Fatalf("expected error for a missing plugin")
+	}
+}
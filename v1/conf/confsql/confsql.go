@@ -0,0 +1,124 @@
+// Package confsql provides a conf.Provider loading the configuration from a
+// database/sql query, for settings centralized in Postgres, MySQL or any
+// other database with a driver.
+//
+// The query either returns two columns, a configuration key ("server.port")
+// and its value, or a single column holding whole documents decoded with a
+// conf.Decoder. Rows are merged in order, later ones overriding earlier ones.
+// Keys are split into levels at the key delimiter of the Config loading the
+// provider, see conf.KeyDelimiterFromContext.
+package confsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// Options configures a Provider.
+type Options struct {
+	// Query selects the configuration rows, with Args as its arguments.
+	Query string
+	Args  []any
+	// Format is the format of documents returned by single column queries,
+	// an extension or a MIME type, decoded with Decoder, usually the Config
+	// the provider is added to.
+	Format  string
+	Decoder conf.Decoder
+	// RefreshInterval makes Watch reload the rows periodically.
+	RefreshInterval time.Duration
+}
+
+// Provider reads the configuration rows returned by a query.
+type Provider struct {
+	db   *sql.DB
+	opts Options
+}
+
+// New returns a provider running opts.Query against db.
+func New(db *sql.DB, opts Options) *Provider {
+	return &Provider{db: db, opts: opts}
+}
+
+// Name returns the query of the provider.
+func (p *Provider) Name() string {
+	return "sql:" + p.opts.Query
+}
+
+// Load runs the query and merges the returned rows.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	rows, err := p.db.QueryContext(ctx, p.opts.Query, p.opts.Args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	sep := conf.KeyDelimiterFromContext(ctx)
+	values := make(map[string]any)
+	switch len(cols) {
+	case 1:
+		if p.opts.Decoder == nil {
+			return nil, errors.New("no decoder set")
+		}
+		for rows.Next() {
+			var doc []byte
+			if err := rows.Scan(&doc); err != nil {
+				return nil, err
+			}
+			if doc == nil {
+				continue
+			}
+			decoded, err := p.opts.Decoder.Decode(doc, p.opts.Format)
+			if err != nil {
+				return nil, err
+			}
+			conf.MergeMaps(values, decoded)
+		}
+	case 2:
+		for rows.Next() {
+			var key, value sql.NullString
+			if err := rows.Scan(&key, &value); err != nil {
+				return nil, err
+			}
+			if !key.Valid || key.String == "" {
+				continue
+			}
+			var v any
+			if value.Valid {
+				v = value.String
+			}
+			conf.SetPath(values, key.String, sep, v)
+		}
+	default:
+		return nil, fmt.Errorf("expected 1 or 2 columns, got %d", len(cols))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Watch calls notify every Options.RefreshInterval until ctx is done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	if p.opts.RefreshInterval <= 0 {
+		return errors.New("no refresh interval configured")
+	}
+	ticker := time.NewTicker(p.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			notify()
+		}
+	}
+}
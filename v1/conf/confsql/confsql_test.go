@@ -0,0 +1,123 @@
+package confsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// tableDriver serves the rows registered for a query text.
+type tableDriver struct {
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+type table struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (d *tableDriver) Open(string) (driver.Conn, error) { return conn{d}, nil }
+
+func (d *tableDriver) set(query string, t *table) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tables[query] = t
+}
+
+type conn struct{ d *tableDriver }
+
+func (c conn) Prepare(query string) (driver.Stmt, error) { return stmt{c.d, query}, nil }
+func (c conn) Close() error                              { return nil }
+func (c conn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type stmt struct {
+	d     *tableDriver
+	query string
+}
+
+func (s stmt) Close() error                               { return nil }
+func (s stmt) NumInput() int                              { return -1 }
+func (s stmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+
+func (s stmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	t := s.d.tables[s.query]
+	return &rows{cols: t.cols, data: append([][]driver.Value(nil), t.rows...)}, nil
+}
+
+type rows struct {
+	cols []string
+	data [][]driver.Value
+}
+
+func (r *rows) Columns() []string { return r.cols }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.data[0])
+	r.data = r.data[1:]
+	return nil
+}
+
+var testDriver = &tableDriver{tables: make(map[string]*table)}
+
+func init() {
+	sql.Register("conftest", testDriver)
+}
+
+func TestProviderRowsAndDocuments(t *testing.T) {
+	db, err := sql.Open("conftest", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	testDriver.set("SELECT doc", &table{cols: []string{"doc"}, rows: [][]driver.Value{
+		{[]byte(`{"server": {"port": 8080, "host": "localhost"}}`)},
+	}})
+	testDriver.set("SELECT k, v", &table{cols: []string{"k", "v"}, rows: [][]driver.Value{
+		{"server.port", "9090"},
+		{"name", "demo"},
+	}})
+
+	cfg := conf.New()
+	cfg.AddProvider(New(db, Options{Query: "SELECT doc", Format: "json", Decoder: cfg}))
+	rowsProvider := New(db, Options{Query: "SELECT k, v", RefreshInterval: 10 * time.Millisecond})
+	cfg.AddProvider(rowsProvider)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.ReadProviders(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetInt("server.port") != 9090 || cfg.GetString("server.host") != "localhost" || cfg.GetString("name") != "demo" {
+		t.Fatalf("unexpected values %v", cfg.AllSettingsFlat())
+	}
+
+	changed := make(chan struct{}, 1)
+	cfg.Subscribe(conf.KeyPrefix("name"), func(conf.ChangeEvent) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	cfg.WatchProviders(ctx)
+	testDriver.set("SELECT k, v", &table{cols: []string{"k", "v"}, rows: [][]driver.Value{{"name", "renamed"}}})
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected refresh to pick up the new rows")
+	}
+	if got := cfg.GetString("name"); got != "renamed" {
+		t.Fatalf("expected refreshed value, got %q", got)
+	}
+}
@@ -0,0 +1,203 @@
+// Package confxds provides a conf.Provider subscribed to a control plane
+// over a long-lived bidirectional gRPC stream, in the style of the xDS
+// protocols used by service meshes.
+//
+// The client opens the stream with a Request naming its node; the control
+// plane pushes Responses carrying either a full snapshot of the values or an
+// incremental update (values to merge and keys to remove). Every response
+// is acknowledged with a Request echoing its version and nonce, or rejected
+// with the previously applied version and an error detail. Messages are
+// encoded as JSON with Codec, so no generated code is needed on either side:
+//
+//	service ConfigDiscoveryService {
+//	  rpc StreamConfig(stream Request) returns (stream Response);
+//	}
+package confxds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"google.golang.org/grpc"
+)
+
+// Method is the full name of the streaming method called on the control
+// plane.
+const Method = "/conf.v1.ConfigDiscoveryService/StreamConfig"
+
+// StreamDesc describes the streaming method.
+var StreamDesc = grpc.StreamDesc{
+	StreamName:    "StreamConfig",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// Request is sent by the client to subscribe and to acknowledge responses.
+type Request struct {
+	Node          string `json:"node,omitempty"`
+	VersionInfo   string `json:"version_info,omitempty"`
+	ResponseNonce string `json:"response_nonce,omitempty"`
+	ErrorDetail   string `json:"error_detail,omitempty"`
+}
+
+// Response is pushed by the control plane.
+type Response struct {
+	VersionInfo string         `json:"version_info"`
+	Nonce       string         `json:"nonce"`
+	Incremental bool           `json:"incremental,omitempty"`
+	Values      map[string]any `json:"values,omitempty"`
+	// Removed lists the keys to delete, their levels separated by the key
+	// delimiter of the Config.
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Codec encodes the messages of the stream as JSON.
+type Codec struct{}
+
+// Marshal encodes v as JSON.
+func (Codec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+// Unmarshal decodes JSON data into v.
+func (Codec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// Name returns the content subtype of the codec.
+func (Codec) Name() string { return "json" }
+
+// Provider holds the latest snapshot pushed by the control plane.
+type Provider struct {
+	conn grpc.ClientConnInterface
+	node string
+
+	mu      sync.Mutex
+	values  map[string]any
+	version string
+}
+
+// New returns a provider subscribing as node through conn.
+func New(conn grpc.ClientConnInterface, node string) *Provider {
+	return &Provider{conn: conn, node: node}
+}
+
+// Name identifies the subscription.
+func (p *Provider) Name() string {
+	return "xds:" + p.node
+}
+
+// Version returns the version of the snapshot currently held.
+func (p *Provider) Version() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.version
+}
+
+// Load returns the snapshot currently held. Before the first snapshot has
+// been received, it subscribes and waits for it.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	p.mu.Lock()
+	received := p.version != ""
+	p.mu.Unlock()
+	if !received {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		errStop := errors.New("stop")
+		err := p.subscribe(ctx, func() error { return errStop })
+		if err != nil && !errors.Is(err, errStop) {
+			return nil, err
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return conf.CloneMap(p.values), nil
+}
+
+// Watch keeps the subscription open until ctx is done, calling notify after
+// every accepted response. Broken streams are reopened with a backoff,
+// resuming from the version held.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	backoff := time.Second
+	for {
+		// Stream errors are not fatal: the subscription is reopened.
+		p.subscribe(ctx, func() error {
+			backoff = time.Second
+			notify()
+			return nil
+		})
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// subscribe runs one stream, calling applied after every accepted response
+// until it returns an error.
+func (p *Provider) subscribe(ctx context.Context, applied func() error) error {
+	sep := conf.KeyDelimiterFromContext(ctx)
+	stream, err := p.conn.NewStream(ctx, &StreamDesc, Method, grpc.ForceCodec(Codec{}))
+	if err != nil {
+		return err
+	}
+	defer stream.CloseSend()
+	if err := stream.SendMsg(&Request{Node: p.node, VersionInfo: p.Version()}); err != nil {
+		return err
+	}
+	for {
+		var resp Response
+		if err := stream.RecvMsg(&resp); err != nil {
+			return err
+		}
+		ack := Request{Node: p.node, ResponseNonce: resp.Nonce}
+		if err := p.apply(&resp, sep); err != nil {
+			ack.VersionInfo = p.Version()
+			ack.ErrorDetail = err.Error()
+			if err := stream.SendMsg(&ack); err != nil {
+				return err
+			}
+			continue
+		}
+		ack.VersionInfo = resp.VersionInfo
+		if err := stream.SendMsg(&ack); err != nil {
+			return err
+		}
+		if err := applied(); err != nil {
+			return err
+		}
+	}
+}
+
+// apply installs a response, or reports why it must be rejected. Removed
+// keys are split at every sep.
+func (p *Provider) apply(resp *Response, sep string) error {
+	if resp.VersionInfo == "" {
+		return errors.New("missing version")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !resp.Incremental {
+		p.values = conf.CloneMap(resp.Values)
+		if p.values == nil {
+			p.values = make(map[string]any)
+		}
+		p.version = resp.VersionInfo
+		return nil
+	}
+	if p.version == "" {
+		return errors.New("incremental update before the first snapshot")
+	}
+	values := conf.CloneMap(p.values)
+	conf.MergeMaps(values, conf.CloneMap(resp.Values))
+	for _, key := range resp.Removed {
+		conf.DeletePath(values, key, sep)
+	}
+	p.values = values
+	p.version = resp.VersionInfo
+	return nil
+}
@@ -0,0 +1,116 @@
+package confxds
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// controlPlane serves a scripted subscription: the first stream receives a
+// full snapshot, the resumed one an incremental update followed by an
+// invalid response.
+func controlPlane(acks chan<- Request) grpc.StreamHandler {
+	return func(_ any, stream grpc.ServerStream) error {
+		var req Request
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		if req.VersionInfo == "" {
+			full := Response{VersionInfo: "1", Nonce: "a", Values: map[string]any{
+				"server": map[string]any{"port": 8080, "host": "localhost"},
+				"debug":  true,
+			}}
+			if err := stream.SendMsg(&full); err != nil {
+				return err
+			}
+			var ack Request
+			if err := stream.RecvMsg(&ack); err != nil {
+				return err
+			}
+			acks <- ack
+			<-stream.Context().Done()
+			return nil
+		}
+		responses := []Response{
+			{VersionInfo: "2", Nonce: "b", Incremental: true, Values: map[string]any{"server": map[string]any{"port": 9090}}, Removed: []string{"debug"}},
+			{Nonce: "c", Incremental: true},
+		}
+		for _, resp := range responses {
+			if err := stream.SendMsg(&resp); err != nil {
+				return err
+			}
+			var ack Request
+			if err := stream.RecvMsg(&ack); err != nil {
+				return err
+			}
+			acks <- ack
+		}
+		<-stream.Context().Done()
+		return nil
+	}
+}
+
+func TestProviderSubscription(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	acks := make(chan Request, 10)
+	srv := grpc.NewServer(grpc.ForceServerCodec(Codec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "conf.v1.ConfigDiscoveryService",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    StreamDesc.StreamName,
+			Handler:       controlPlane(acks),
+			ServerStreams: true,
+			ClientStreams: true,
+		}},
+	}, struct{}{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := New(conn, "node-1")
+	cfg := conf.New()
+	cfg.AddProvider(p)
+	if err := cfg.ReadProviders(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetInt("server.port") != 8080 || !cfg.GetBool("debug") {
+		t.Fatalf("unexpected snapshot %v", cfg.AllSettingsFlat())
+	}
+	if ack := <-acks; ack.VersionInfo != "1" || ack.ResponseNonce != "a" || ack.ErrorDetail != "" {
+		t.Fatalf("unexpected ack %+v", ack)
+	}
+
+	cfg.WatchProviders(ctx)
+	if ack := <-acks; ack.VersionInfo != "2" || ack.ResponseNonce != "b" {
+		t.Fatalf("unexpected ack %+v", ack)
+	}
+	if nack := <-acks; nack.VersionInfo != "2" || nack.ResponseNonce != "c" || nack.ErrorDetail == "" {
+		t.Fatalf("expected invalid response to be rejected, got %+v", nack)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for cfg.GetInt("server.port") != 9090 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected incremental update to be applied, got %v", cfg.AllSettingsFlat())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := cfg.AllSettingsFlat()["debug"]; ok || cfg.GetString("server.host") != "localhost" {
+		t.Fatalf("unexpected values after incremental update %v", cfg.AllSettingsFlat())
+	}
+}
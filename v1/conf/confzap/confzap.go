@@ -0,0 +1,65 @@
+// Package confzap adapts go.uber.org/zap to the conf package: it provides a
+// conf.Logger backed by a *zap.Logger and an object marshaler that logs the
+// redacted effective configuration.
+package confzap
+
+import (
+	"sort"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type logger struct {
+	sugar *zap.SugaredLogger
+}
+
+// Logger returns a conf.Logger that writes through l.
+func Logger(l *zap.Logger) conf.Logger {
+	return logger{sugar: l.Sugar()}
+}
+
+func (l logger) Warn(msg string, args ...any) {
+	l.sugar.Warnw(msg, args...)
+}
+
+func (l logger) Error(msg string, args ...any) {
+	l.sugar.Errorw(msg, args...)
+}
+
+// Object returns a field logging the redacted configuration of cfg as a
+// nested object:
+//
+//	log.Info("loaded config", confzap.Object("config", cfg))
+func Object(key string, cfg *conf.Config) zap.Field {
+	return zap.Object(key, Marshaler(cfg))
+}
+
+// Marshaler returns a zapcore.ObjectMarshaler for the redacted configuration
+// of cfg.
+func Marshaler(cfg *conf.Config) zapcore.ObjectMarshaler {
+	return settings(cfg.Redacted())
+}
+
+type settings map[string]any
+
+func (s settings) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if nested, ok := s[k].(map[string]any); ok {
+			if err := enc.AddObject(k, settings(nested)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.AddReflected(k, s[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
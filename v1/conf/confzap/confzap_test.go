@@ -0,0 +1,35 @@
+package confzap
+
+import (
+	"testing"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestObjectRedactsSecrets(t *testing.T) {
+	cfg := conf.New()
+	cfg.MergeConfigMap(map[string]any{
+		"server":   map[string]any{"port": 8080},
+		"database": map[string]any{"password": "hunter2"},
+	})
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	log := zap.New(core)
+	log.Info("loaded config", Object("config", cfg))
+	Logger(log).Warn("conf: something", "key", "value")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected two entries, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()["config"].(map[string]any)
+	if db := fields["database"].(map[string]any); db["password"] != "******" {
+		t.Fatalf("expected redacted password, got %#v", db)
+	}
+	if entries[1].Level != zapcore.WarnLevel || entries[1].ContextMap()["key"] != "value" {
+		t.Fatalf("unexpected warn entry %+v", entries[1])
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/mirkobrombin/go-conf-builder/v1/conf/confzap
+
+go 1.23.4
+
+require (
+	github.com/mirkobrombin/go-conf-builder v0.1.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/BurntSushi/toml v1.5.0 // indirect
+	github.com/fsnotify/fsnotify v1.9.0 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/crypto v0.33.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+	gopkg.in/ini.v1 v1.67.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
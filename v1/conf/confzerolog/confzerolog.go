@@ -0,0 +1,53 @@
+// Package confzerolog adapts github.com/rs/zerolog to the conf package: it
+// provides a conf.Logger backed by a zerolog.Logger and an object marshaler
+// that logs the redacted effective configuration.
+package confzerolog
+
+import (
+	"sort"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"github.com/rs/zerolog"
+)
+
+type logger struct {
+	zl zerolog.Logger
+}
+
+// Logger returns a conf.Logger that writes through zl.
+func Logger(zl zerolog.Logger) conf.Logger {
+	return logger{zl: zl}
+}
+
+func (l logger) Warn(msg string, args ...any) {
+	l.zl.Warn().Fields(args).Msg(msg)
+}
+
+func (l logger) Error(msg string, args ...any) {
+	l.zl.Error().Fields(args).Msg(msg)
+}
+
+// Marshaler returns a zerolog.LogObjectMarshaler for the redacted
+// configuration of cfg:
+//
+//	log.Info().Object("config", confzerolog.Marshaler(cfg)).Msg("loaded config")
+func Marshaler(cfg *conf.Config) zerolog.LogObjectMarshaler {
+	return settings(cfg.Redacted())
+}
+
+type settings map[string]any
+
+func (s settings) MarshalZerologObject(e *zerolog.Event) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if nested, ok := s[k].(map[string]any); ok {
+			e.Object(k, settings(nested))
+			continue
+		}
+		e.Interface(k, s[k])
+	}
+}
@@ -0,0 +1,34 @@
+package confzerolog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+	"github.com/rs/zerolog"
+)
+
+func TestMarshalerRedactsSecrets(t *testing.T) {
+	cfg := conf.New()
+	cfg.MergeConfigMap(map[string]any{
+		"server":   map[string]any{"port": 8080},
+		"database": map[string]any{"password": "hunter2"},
+	})
+
+	var buf bytes.Buffer
+	zl := zerolog.New(&buf)
+	zl.Info().Object("config", Marshaler(cfg)).Msg("loaded config")
+	Logger(zl).Error("conf: failed", "error", "boom")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two lines, got %q", buf.String())
+	}
+	if want := `"config":{"database":{"password":"******"},"server":{"port":8080}}`; !strings.Contains(lines[0], want) {
+		t.Fatalf("expected %s in %s", want, lines[0])
+	}
+	if !strings.Contains(lines[1], `"level":"error"`) || !strings.Contains(lines[1], `"error":"boom"`) {
+		t.Fatalf("unexpected error line %s", lines[1])
+	}
+}
@@ -0,0 +1,178 @@
+// Package confzk provides a conf.Provider reading the configuration from a
+// ZooKeeper znode tree. Below the root znode, every znode with children is a
+// section and every leaf znode holds the value of a key as text:
+//
+//	/config/app/server/port = "8080"  ->  server.port: "8080"
+//
+// The provider implements conf.ProviderWatcher, re-arming ZooKeeper's
+// one-shot watches after every change.
+package confzk
+
+import (
+	"context"
+	"path"
+
+	"github.com/go-zookeeper/zk"
+)
+
+// Client is the part of *zk.Conn used by the provider.
+type Client interface {
+	Children(path string) ([]string, *zk.Stat, error)
+	ChildrenW(path string) ([]string, *zk.Stat, <-chan zk.Event, error)
+	Get(path string) ([]byte, *zk.Stat, error)
+	GetW(path string) ([]byte, *zk.Stat, <-chan zk.Event, error)
+	ExistsW(path string) (bool, *zk.Stat, <-chan zk.Event, error)
+}
+
+// Provider reads the tree below a root znode.
+type Provider struct {
+	client Client
+	root   string
+}
+
+// New returns a provider reading the tree below root through client,
+// usually a *zk.Conn.
+func New(client Client, root string) *Provider {
+	return &Provider{client: client, root: root}
+}
+
+// Name identifies the root znode.
+func (p *Provider) Name() string {
+	return "zk:" + p.root
+}
+
+// Load reads the whole tree below the root znode.
+func (p *Provider) Load(ctx context.Context) (map[string]any, error) {
+	values := make(map[string]any)
+	children, _, err := p.client.Children(p.root)
+	if err == zk.ErrNoNode {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		v, err := p.load(path.Join(p.root, child))
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			values[child] = v
+		}
+	}
+	return values, nil
+}
+
+// load returns the value of a leaf znode or the section below it, nil when
+// the znode disappeared meanwhile.
+func (p *Provider) load(node string) (any, error) {
+	children, _, err := p.client.Children(node)
+	if err == zk.ErrNoNode {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(children) == 0 {
+		data, _, err := p.client.Get(node)
+		if err == zk.ErrNoNode {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	section := make(map[string]any)
+	for _, child := range children {
+		v, err := p.load(path.Join(node, child))
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			section[child] = v
+		}
+	}
+	return section, nil
+}
+
+// Watch calls notify after every change of a znode below the root until ctx
+// is done.
+func (p *Provider) Watch(ctx context.Context, notify func()) error {
+	for {
+		var events []<-chan zk.Event
+		if err := p.watch(p.root, &events); err != nil {
+			return err
+		}
+		round, stop := context.WithCancel(ctx)
+		fired := make(chan struct{}, 1)
+		for _, ch := range events {
+			go func(ch <-chan zk.Event) {
+				select {
+				case <-ch:
+					select {
+					case fired <- struct{}{}:
+					default:
+					}
+				case <-round.Done():
+				}
+			}(ch)
+		}
+		select {
+		case <-ctx.Done():
+			stop()
+			return nil
+		case <-fired:
+			stop()
+			notify()
+		}
+	}
+}
+
+// watch arms a data and a children watch on node and every znode below it,
+// or an exists watch when node is missing.
+func (p *Provider) watch(node string, events *[]<-chan zk.Event) error {
+	children, _, childEvents, err := p.client.ChildrenW(node)
+	if err == zk.ErrNoNode {
+		return p.watchExists(node, events)
+	}
+	if err != nil {
+		return err
+	}
+	*events = append(*events, childEvents)
+	if len(children) == 0 && node != p.root {
+		_, _, dataEvents, err := p.client.GetW(node)
+		if err == zk.ErrNoNode {
+			return p.watchExists(node, events)
+		}
+		if err != nil {
+			return err
+		}
+		*events = append(*events, dataEvents)
+	}
+	for _, child := range children {
+		if err := p.watch(path.Join(node, child), events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchExists arms an exists watch on node, which was found missing, so that
+// its creation is noticed. A node created in the meantime fires at once.
+func (p *Provider) watchExists(node string, events *[]<-chan zk.Event) error {
+	exists, _, existEvents, err := p.client.ExistsW(node)
+	if err != nil {
+		return err
+	}
+	if exists {
+		created := make(chan zk.Event, 1)
+		created <- zk.Event{Type: zk.EventNodeCreated, Path: node}
+		existEvents = created
+	}
+	*events = append(*events, existEvents)
+	return nil
+}
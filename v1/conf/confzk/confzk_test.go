@@ -0,0 +1,176 @@
+package confzk
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	"github.com/mirkobrombin/go-conf-builder/v1/conf"
+)
+
+// memoryTree is an in-memory Client holding leaf znodes by path.
+type memoryTree struct {
+	mu       sync.Mutex
+	leaves   map[string]string
+	watchers []chan zk.Event
+}
+
+func (m *memoryTree) Children(node string) ([]string, *zk.Stat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]bool)
+	found := false
+	for leaf := range m.leaves {
+		if leaf == node {
+			found = true
+			continue
+		}
+		if rest, ok := strings.CutPrefix(leaf, node+"/"); ok {
+			found = true
+			seen[strings.Split(rest, "/")[0]] = true
+		}
+	}
+	if !found {
+		return nil, nil, zk.ErrNoNode
+	}
+	children := make([]string, 0, len(seen))
+	for child := range seen {
+		children = append(children, child)
+	}
+	sort.Strings(children)
+	return children, &zk.Stat{}, nil
+}
+
+// ChildrenW and GetW arm no watch on a missing znode, like ZooKeeper.
+func (m *memoryTree) ChildrenW(node string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	children, stat, err := m.Children(node)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return children, stat, m.arm(), nil
+}
+
+func (m *memoryTree) Get(node string) ([]byte, *zk.Stat, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.leaves[node]
+	if !ok {
+		return nil, nil, zk.ErrNoNode
+	}
+	return []byte(v), &zk.Stat{}, nil
+}
+
+func (m *memoryTree) GetW(node string) ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	data, stat, err := m.Get(node)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return data, stat, m.arm(), nil
+}
+
+func (m *memoryTree) ExistsW(node string) (bool, *zk.Stat, <-chan zk.Event, error) {
+	_, _, err := m.Children(node)
+	return err == nil, &zk.Stat{}, m.arm(), nil
+}
+
+func (m *memoryTree) arm() <-chan zk.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch := make(chan zk.Event, 1)
+	m.watchers = append(m.watchers, ch)
+	return ch
+}
+
+func (m *memoryTree) set(node, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.leaves[node] = value
+	for _, ch := range m.watchers {
+		ch <- zk.Event{Type: zk.EventNodeDataChanged, Path: node}
+	}
+	m.watchers = nil
+}
+
+func (m *memoryTree) armed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.watchers) > 0
+}
+
+func TestProviderLoadAndWatch(t *testing.T) {
+	root := "/config/app"
+	tree := &memoryTree{leaves: map[string]string{
+		path.Join(root, "server/port"): "8080",
+		path.Join(root, "server/host"): "localhost",
+		path.Join(root, "name"):        "demo",
+	}}
+	cfg := conf.New()
+	cfg.AddProvider(New(tree, root))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.ReadProviders(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetInt("server.port") != 8080 || cfg.GetString("server.host") != "localhost" || cfg.GetString("name") != "demo" {
+		t.Fatalf("unexpected values %v", cfg.AllSettingsFlat())
+	}
+
+	changed := make(chan struct{}, 1)
+	cfg.Subscribe(conf.KeyPrefix("server.port"), func(conf.ChangeEvent) { changed <- struct{}{} })
+	cfg.WatchProviders(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for !tree.armed() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected watches to be armed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tree.set(path.Join(root, "server/port"), "9090")
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected change event after a znode update")
+	}
+	if got := cfg.GetInt("server.port"); got != 9090 {
+		t.Fatalf("expected reloaded port, got %d", got)
+	}
+}
+
+func TestProviderWatchMissingRoot(t *testing.T) {
+	root := "/config/app"
+	tree := &memoryTree{leaves: map[string]string{}}
+	cfg := conf.New()
+	cfg.AddProvider(New(tree, root))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := cfg.ReadProviders(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan struct{}, 1)
+	cfg.Subscribe(conf.KeyPrefix("name"), func(conf.ChangeEvent) { changed <- struct{}{} })
+	cfg.WatchProviders(ctx)
+	deadline := time.Now().Add(2 * time.Second)
+	for !tree.armed() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected an exists watch on the missing root")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tree.set(path.Join(root, "name"), "demo")
+
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected change event after the root was created")
+	}
+	if got := cfg.GetString("name"); got != "demo" {
+		t.Fatalf("expected the new znode to be loaded, got %q", got)
+	}
+}
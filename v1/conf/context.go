@@ -0,0 +1,56 @@
+package conf
+
+import "context"
+
+type configKey struct{}
+
+type snapshotKey struct{}
+
+// WithContext returns a copy of ctx carrying c, to be retrieved with
+// FromContext further down a call chain.
+func WithContext(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, c)
+}
+
+// FromContext returns the Config attached to ctx by WithContext.
+func FromContext(ctx context.Context) (*Config, bool) {
+	c, ok := ctx.Value(configKey{}).(*Config)
+	return c, ok && c != nil
+}
+
+// WithSnapshot returns a copy of ctx carrying s, typically a per-request view
+// built with Snapshot.With, so that a request is served with one consistent
+// configuration even if a reload happens meanwhile.
+func WithSnapshot(ctx context.Context, s Snapshot) context.Context {
+	return context.WithValue(ctx, snapshotKey{}, s)
+}
+
+// SnapshotFromContext returns the Snapshot attached to ctx by WithSnapshot
+// or, failing that, the current Snapshot of the Config attached by
+// WithContext.
+func SnapshotFromContext(ctx context.Context) (Snapshot, bool) {
+	if s, ok := ctx.Value(snapshotKey{}).(Snapshot); ok {
+		return s, true
+	}
+	if c, ok := FromContext(ctx); ok {
+		return c.Snapshot(), true
+	}
+	return Snapshot{}, false
+}
+
+// With returns a copy of s in which overrides are merged into the override
+// layer, so they win over every other source, including Set and environment
+// variables, leaving s unchanged. It suits per-request adjustments such as
+// tenant specific settings.
+func (s Snapshot) With(overrides map[string]any) Snapshot {
+	if s.s == nil {
+		s.s = &state{delim: defaultKeyDelimiter}
+	}
+	derived := *s.s
+	extra := normalizeLoadedMap(cloneMap(overrides))
+	if derived.fold {
+		extra = foldKeys(extra)
+	}
+	derived.overrides = mergeMaps(cloneMap(s.s.overrides), extra)
+	return Snapshot{s: derived.bind()}
+}
@@ -0,0 +1,44 @@
+package conf
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextCarriage(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"limits": map[string]any{"rps": 100, "burst": 10}})
+
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatalf("expected no config in an empty context")
+	}
+	ctx := WithContext(context.Background(), c)
+	if got, ok := FromContext(ctx); !ok || got != c {
+		t.Fatalf("expected config from context")
+	}
+	if s, ok := SnapshotFromContext(ctx); !ok || s.GetInt("limits.rps") != 100 {
+		t.Fatalf("expected snapshot of the attached config")
+	}
+
+	tenant := c.Snapshot().With(map[string]any{"limits": map[string]any{"rps": 5}})
+	ctx = WithSnapshot(ctx, tenant)
+	s, _ := SnapshotFromContext(ctx)
+	if s.GetInt("limits.rps") != 5 || s.GetInt("limits.burst") != 10 {
+		t.Fatalf("expected tenant override merged over the config, got %v", s.Settings())
+	}
+	if c.GetInt("limits.rps") != 100 {
+		t.Fatalf("expected override not to leak into the config")
+	}
+
+	t.Setenv("APP_LIMITS_BURST", "50")
+	c.SetEnvPrefix("APP")
+	c.AutomaticEnv()
+	c.Set("limits.rps", 200)
+	tenant = c.Snapshot().With(map[string]any{"limits": map[string]any{"rps": 5, "burst": 1}})
+	if got := tenant.GetInt("limits.rps"); got != 5 {
+		t.Fatalf("expected the request value to win over Set, got %d", got)
+	}
+	if got := tenant.GetInt("limits.burst"); got != 1 {
+		t.Fatalf("expected the request value to win over the environment, got %d", got)
+	}
+}
@@ -0,0 +1,234 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The to* helpers implement the weak conversions used by the getters. They
+// report false when the value cannot be represented in the requested type.
+
+// parseOptions holds the opt-in parsing extensions applied to string values.
+type parseOptions struct {
+	humanNumbers      bool
+	extendedDurations bool
+	bools             *boolWords
+}
+
+func (o parseOptions) toInt(v any) (int, bool) {
+	switch val := v.(type) {
+	case int:
+		return val, true
+	case int64:
+		return int(val), true
+	case uint64:
+		return int(val), val <= math.MaxInt
+	case float64:
+		return int(val), true
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return int(i), true
+		}
+		f, err := val.Float64()
+		return int(f), err == nil && f >= math.MinInt && f < math.MaxInt
+	case string:
+		if o.humanNumbers {
+			return parseHumanInt(val)
+		}
+		i, err := strconv.Atoi(val)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func (o parseOptions) toBool(v any) (bool, bool) {
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case string:
+		return o.parseBool(val)
+	case int:
+		return val != 0, true
+	case float64:
+		return val != 0, true
+	}
+	return false, false
+}
+
+func (o parseOptions) toFloat64(v any) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case json.Number:
+		f, err := val.Float64()
+		return f, err == nil
+	case string:
+		if o.humanNumbers {
+			return parseHumanFloat(val)
+		}
+		f, err := strconv.ParseFloat(val, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func (o parseOptions) toDuration(v any) (time.Duration, bool) {
+	switch val := v.(type) {
+	case time.Duration:
+		return val, true
+	case int:
+		return time.Duration(val), true
+	case int64:
+		return time.Duration(val), true
+	case float64:
+		return time.Duration(val), true
+	case json.Number:
+		i, err := val.Int64()
+		return time.Duration(i), err == nil
+	case string:
+		if o.extendedDurations {
+			return parseExtendedDuration(val)
+		}
+		d, err := time.ParseDuration(val)
+		if err == nil {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func (o parseOptions) toIntSlice(v any) ([]int, bool) {
+	switch slice := v.(type) {
+	case []int:
+		return append([]int(nil), slice...), true
+	case []any:
+		result := make([]int, 0, len(slice))
+		for _, item := range slice {
+			i, ok := o.toInt(item)
+			if !ok {
+				return nil, false
+			}
+			result = append(result, i)
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func toStringMap(v any) (map[string]any, bool) {
+	switch val := v.(type) {
+	case map[string]any:
+		return cloneMap(val), true
+	case map[string]string:
+		res := make(map[string]any, len(val))
+		for k, item := range val {
+			res[k] = item
+		}
+		return res, true
+	case map[any]any:
+		res := make(map[string]any, len(val))
+		for k, item := range val {
+			res[fmt.Sprint(k)] = item
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+func toStringMapString(v any) (map[string]string, bool) {
+	switch val := v.(type) {
+	case map[string]string:
+		copy := make(map[string]string, len(val))
+		for k, item := range val {
+			copy[k] = item
+		}
+		return copy, true
+	case map[string]any:
+		res := make(map[string]string, len(val))
+		for k, item := range val {
+			res[k] = stringify(item)
+		}
+		return res, true
+	case map[any]any:
+		res := make(map[string]string, len(val))
+		for k, item := range val {
+			res[fmt.Sprint(k)] = stringify(item)
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+func toStringMapStringSlice(v any) (map[string][]string, bool) {
+	switch val := v.(type) {
+	case map[string][]string:
+		res := make(map[string][]string, len(val))
+		for k, item := range val {
+			copy := append([]string(nil), item...)
+			res[k] = copy
+		}
+		return res, true
+	case map[string]any:
+		res := make(map[string][]string, len(val))
+		for k, item := range val {
+			res[k] = toStringSlice(item)
+		}
+		return res, true
+	case map[any]any:
+		res := make(map[string][]string, len(val))
+		for k, item := range val {
+			res[fmt.Sprint(k)] = toStringSlice(item)
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+func stringify(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func toStringSlice(v any) []string {
+	switch val := v.(type) {
+	case []string:
+		return append([]string(nil), val...)
+	case []any:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			result = append(result, stringify(item))
+		}
+		return result
+	case string:
+		if val == "" {
+			return []string{}
+		}
+		parts := strings.Split(val, ",")
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+		}
+		return parts
+	default:
+		return nil
+	}
+}
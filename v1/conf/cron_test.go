@@ -0,0 +1,37 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	from := time.Date(2026, time.January, 31, 10, 7, 30, 0, time.UTC) // a Saturday
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 */6 * * *", time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.January, 31, 10, 15, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2026, time.February, 2, 9, 30, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 7", time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"@every 10m", from.Add(10 * time.Minute)},
+	}
+	for _, tc := range cases {
+		next, err := parseCron(tc.spec)
+		if err != nil {
+			t.Fatalf("expected %q to parse, got %v", tc.spec, err)
+		}
+		if got := next(from); !got.Equal(tc.want) {
+			t.Fatalf("expected %q to fire at %s, got %s", tc.spec, tc.want, got)
+		}
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "5-1 * * * *", "*/0 * * * *", "0 0 30 2 *", "@every 1ms", "@every soon"} {
+		if _, err := parseCron(spec); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+}
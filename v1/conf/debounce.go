@@ -0,0 +1,10 @@
+package conf
+
+import "time"
+
+// reloadDebounce is how long the watcher waits for further write events
+// before reloading the config file. Editors and os.WriteFile truncate the
+// file before writing it, and a reload landing in between would publish an
+// empty configuration, so a burst of events is reloaded once, after it has
+// settled.
+const reloadDebounce = 100 * time.Millisecond
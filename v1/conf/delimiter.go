@@ -0,0 +1,63 @@
+package conf
+
+import "context"
+
+// defaultKeyDelimiter separates the levels of nested keys unless
+// SetKeyDelimiter changes it.
+const defaultKeyDelimiter = "."
+
+// SetKeyDelimiter sets the string separating the levels of nested keys, "."
+// by default. Keys whose names contain dots, such as domain names, can then
+// be reached with another delimiter:
+//
+//	cfg.SetKeyDelimiter("::")
+//	cfg.GetInt("hosts::example.com::port")
+//
+// The delimiter applies to getters, Set, Unset, KeyPrefix, the keys returned
+// by AllKeys and the key patterns of LockKey, MarkSecret and friends, and is
+// handed to providers through KeyDelimiterFromContext. It is replaced
+// by "_" when mapping keys to environment variables. Set it before loading
+// any configuration. An empty delimiter restores the default.
+func (c *Config) SetKeyDelimiter(delim string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.keyDelim = delim
+	c.publishLocked()
+}
+
+// delimLocked returns the key delimiter in effect. The caller must hold c.mu.
+func (c *Config) delimLocked() string {
+	if c.keyDelim == "" {
+		return defaultKeyDelimiter
+	}
+	return c.keyDelim
+}
+
+// KeyDelimiter returns the key delimiter in effect, for code that builds or
+// splits keys on behalf of c.
+func (c *Config) KeyDelimiter() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.delimLocked()
+}
+
+// KeyDelimiter returns the key delimiter of the Config s was taken from.
+func (s Snapshot) KeyDelimiter() string {
+	if s.s == nil || s.s.delim == "" {
+		return defaultKeyDelimiter
+	}
+	return s.s.delim
+}
+
+// KeyDelimiterFromContext returns the key delimiter of the Config attached
+// to ctx by WithContext, or "." when there is none. ReadProviders and
+// WatchProviders attach their Config to the context handed to providers, so
+// a provider building nested maps from flat keys splits them with
+// KeyDelimiterFromContext(ctx).
+func KeyDelimiterFromContext(ctx context.Context) string {
+	if c, ok := FromContext(ctx); ok {
+		return c.KeyDelimiter()
+	}
+	return defaultKeyDelimiter
+}
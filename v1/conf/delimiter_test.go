@@ -0,0 +1,101 @@
+package conf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetKeyDelimiter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	data := "hosts:\n  example.com:\n    port: 443\n  10.0.0.1:\n    port: 8080\n"
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_HOSTS_LOCAL_PORT", "9000")
+	c := New()
+	c.SetKeyDelimiter("::")
+	c.SetEnvPrefix("APP")
+	c.SetDefault("hosts::local::port", 80)
+	c.SetConfigFile(file)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetInt("hosts::example.com::port"); got != 443 {
+		t.Fatalf("expected 443, got %d", got)
+	}
+	if got := c.GetInt("hosts::10.0.0.1::port"); got != 8080 {
+		t.Fatalf("expected 8080, got %d", got)
+	}
+	if got := c.GetInt("hosts::local::port"); got != 9000 {
+		t.Fatalf("expected env override 9000, got %d", got)
+	}
+
+	c.Set("hosts::example.com::tls", true)
+	if !c.GetBool("hosts::example.com::tls") {
+		t.Fatalf("expected override to be visible")
+	}
+	c.Unset("hosts::10.0.0.1")
+	if c.IsSet("hosts::10.0.0.1::port") {
+		t.Fatalf("expected hosts::10.0.0.1 to be unset")
+	}
+
+	want := []string{"hosts::example.com::port", "hosts::example.com::tls", "hosts::local::port"}
+	if got := c.AllKeys(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected keys %v, got %v", want, got)
+	}
+	sub := c.Sub("hosts::example.com")
+	if sub == nil || sub.GetInt("port") != 443 {
+		t.Fatalf("expected sub config with port 443")
+	}
+}
+
+type delimiterProvider struct{}
+
+func (delimiterProvider) Name() string { return "delimiter" }
+
+func (delimiterProvider) Load(ctx context.Context) (map[string]any, error) {
+	return map[string]any{"sep": KeyDelimiterFromContext(ctx)}, nil
+}
+
+func TestKeyDelimiterThreading(t *testing.T) {
+	c := New()
+	c.SetKeyDelimiter("::")
+	if got := c.KeyDelimiter(); got != "::" {
+		t.Fatalf("expected delimiter ::, got %q", got)
+	}
+	if got := KeyDelimiterFromContext(context.Background()); got != "." {
+		t.Fatalf("expected the default delimiter without a Config, got %q", got)
+	}
+	c.AddProvider(delimiterProvider{})
+	if err := c.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("sep"); got != "::" {
+		t.Fatalf("expected providers to see delimiter ::, got %q", got)
+	}
+
+	var keys []string
+	c.Subscribe(KeyPrefix("hosts::example.com"), func(ev ChangeEvent) { keys = append(keys, ev.Key) })
+	c.Set("hosts::example.com::port", 443)
+	c.Set("hosts::example.org::port", 80)
+	if !reflect.DeepEqual(keys, []string{"hosts::example.com::port"}) {
+		t.Fatalf("expected KeyPrefix to follow the delimiter, got %v", keys)
+	}
+
+	var port map[string]any
+	if err := c.BindVar(&port, "hosts::example.com"); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("hosts::example.com::port", 8443)
+	if got := port["port"]; got != 8443 {
+		t.Fatalf("expected the bound parent key to follow the change, got %v", got)
+	}
+
+	if got := (Snapshot{}).With(map[string]any{"a": map[string]any{"b": 1}}).GetInt("a.b"); got != 1 {
+		t.Fatalf("expected a zero snapshot to use the default delimiter, got %d", got)
+	}
+}
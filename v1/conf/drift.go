@@ -0,0 +1,68 @@
+package conf
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// ChangeSourceDrift marks the differences reported by DriftFrom and
+// WatchDrift.
+const ChangeSourceDrift = "drift"
+
+// DriftFrom returns one event per effective key whose live value differs
+// from reference, typically a Snapshot pinned after startup, in key order.
+// Old holds the reference value and New the live one; keys only present on
+// one side are reported as added or removed. An empty result means the live
+// configuration matches the reference.
+func (c *Config) DriftFrom(reference Snapshot) []ChangeEvent {
+	ref := reference.s
+	if ref == nil {
+		ref = &state{delim: defaultKeyDelimiter}
+	}
+	return diffStates(ref, c.load(), ChangeSourceDrift)
+}
+
+// WatchDrift compares the live configuration with reference every interval
+// and calls fn with the drift, as returned by DriftFrom, whenever it differs
+// from the previous check, so out-of-band edits on long-running hosts are
+// detected. fn is called with an empty slice when the configuration returns
+// to the reference. Checking stops when ctx is done.
+func (c *Config) WatchDrift(ctx context.Context, reference Snapshot, interval time.Duration, fn func([]ChangeEvent)) {
+	if fn == nil || interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last []ChangeEvent
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				drift := c.DriftFrom(reference)
+				if sameDrift(last, drift) {
+					continue
+				}
+				last = drift
+				fn(drift)
+			}
+		}
+	}()
+}
+
+// sameDrift reports whether a and b describe the same differences,
+// regardless of when they were computed.
+func sameDrift(a, b []ChangeEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || a[i].Type != b[i].Type ||
+			!reflect.DeepEqual(a[i].Old, b[i].Old) || !reflect.DeepEqual(a[i].New, b[i].New) {
+			return false
+		}
+	}
+	return true
+}
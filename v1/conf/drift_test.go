@@ -0,0 +1,59 @@
+package conf
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDriftFrom(t *testing.T) {
+	c := New()
+	c.SetDefault("server.port", 8080)
+	c.MergeConfigMap(map[string]any{"server": map[string]any{"host": "localhost"}})
+	reference := c.Snapshot()
+	if drift := c.DriftFrom(reference); len(drift) != 0 {
+		t.Fatalf("expected no drift, got %+v", drift)
+	}
+
+	c.Set("server.port", 9090)
+	c.Set("server.debug", true)
+	drift := c.DriftFrom(reference)
+	if len(drift) != 2 {
+		t.Fatalf("expected two drifted keys, got %+v", drift)
+	}
+	if drift[0].Key != "server.debug" || drift[0].Type != ChangeAdded || drift[0].Source != ChangeSourceDrift {
+		t.Fatalf("unexpected drift %+v", drift[0])
+	}
+	if drift[1].Key != "server.port" || drift[1].Old != 8080 || drift[1].New != 9090 {
+		t.Fatalf("unexpected drift %+v", drift[1])
+	}
+}
+
+func TestWatchDrift(t *testing.T) {
+	c := New()
+	c.SetDefault("mode", "safe")
+	reference := c.Snapshot()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reports := make(chan []ChangeEvent, 4)
+	c.WatchDrift(ctx, reference, 5*time.Millisecond, func(drift []ChangeEvent) { reports <- drift })
+
+	c.Set("mode", "unsafe")
+	select {
+	case drift := <-reports:
+		if len(drift) != 1 || drift[0].Key != "mode" || drift[0].New != "unsafe" {
+			t.Fatalf("unexpected drift %+v", drift)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected drift to be reported")
+	}
+	c.Unset("mode")
+	select {
+	case drift := <-reports:
+		if len(drift) != 0 {
+			t.Fatalf("expected the drift to clear, got %+v", drift)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the return to the reference to be reported")
+	}
+}
@@ -0,0 +1,75 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// DumpOption customizes the output of Dump.
+type DumpOption func(*dumpOptions)
+
+type dumpOptions struct {
+	sources bool
+	mask    string
+}
+
+// DumpSources annotates every key with the layer its value came from.
+func DumpSources() DumpOption {
+	return func(o *dumpOptions) {
+		o.sources = true
+	}
+}
+
+// DumpMask replaces the string printed in place of secret values.
+func DumpMask(mask string) DumpOption {
+	return func(o *dumpOptions) {
+		o.mask = mask
+	}
+}
+
+// Dump writes the effective configuration to w, one "key = value" line per
+// leaf key in sorted order. Values of secret keys are masked, see MarkSecret.
+func (c *Config) Dump(w io.Writer, opts ...DumpOption) error {
+	o := dumpOptions{mask: redactedValue}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := c.load()
+	secrets := c.secretSet()
+	for _, key := range s.keys() {
+		v, src, ok := s.resolve(key)
+		if !ok {
+			continue
+		}
+		text := o.mask
+		if !secrets.has(key) {
+			text = formatValue(secrets.redact(key, v, o.mask))
+		}
+		line := fmt.Sprintf("%s = %s", key, text)
+		if o.sources {
+			line += "  # " + string(src)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns the redacted dump of the effective configuration, so a
+// Config can be printed safely.
+func (c *Config) String() string {
+	var buf bytes.Buffer
+	_ = c.Dump(&buf)
+	return buf.String()
+}
+
+func formatValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
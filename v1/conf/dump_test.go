@@ -0,0 +1,109 @@
+package conf
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDumpRedactsSecrets(t *testing.T) {
+	c := New()
+	c.SetEnvPrefix("DUMP")
+	c.SetDefault("server.port", 8080)
+	c.MergeConfigMap(map[string]any{
+		"database": map[string]any{"user": "app", "password": "hunter2"},
+		"stripe":   map[string]any{"key": "sk_live"},
+		"hosts":    []any{"a", "b"},
+	})
+	c.MarkSecret("stripe.*")
+
+	os.Setenv("DUMP_SERVER_PORT", "9000")
+	defer os.Unsetenv("DUMP_SERVER_PORT")
+
+	var buf bytes.Buffer
+	if err := c.Dump(&buf, DumpSources()); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		`database.password = ******  # config`,
+		`database.user = "app"  # config`,
+		`hosts = ["a","b"]  # config`,
+		`server.port = "9000"  # env`,
+		`stripe.key = ******  # config`,
+		``,
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected dump:\n%s\nwant:\n%s", got, want)
+	}
+
+	if strings.Contains(c.String(), "hunter2") {
+		t.Fatalf("expected String to redact secrets")
+	}
+
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"users": []any{
+		map[string]any{"name": "a", "password": "s3cr3t"},
+	}}})
+	buf.Reset()
+	if err := c.Dump(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); strings.Contains(got, "s3cr3t") || !strings.Contains(got, `db.users = [{"name":"a","password":"******"}]`) {
+		t.Fatalf("expected secrets inside lists to be redacted, got:\n%s", got)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"security.*", "security.tls.cert", true},
+		{"security.*", "securityx", false},
+		{"*.password", "db.password", true},
+		{"a*c*e", "abcde", true},
+		{"exact", "exact", true},
+		{"exact", "exactly", false},
+	}
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.key); got != tt.want {
+			t.Fatalf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestDumpResolvesWithoutLock(t *testing.T) {
+	dir := t.TempDir()
+	c := New()
+	c.Require("region")
+	c.SetDefaultFunc("region", func(*Config) any {
+		// A computed default may call back into the Config it belongs to.
+		c.MarkSecret("token")
+		return "eu"
+	})
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, []byte("port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c.SetConfigFile(file)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Dump(io.Discard)
+		c.LogValue()
+		c.Tree()
+		c.ResolveAll()
+		c.ExportK8sConfigMap("app", "")
+		c.WriteConfigAs(filepath.Join(dir, "out.yaml"))
+		c.Validate()
+		c.CheckInConfig()
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected resolution not to hold the config lock")
+	}
+}
@@ -0,0 +1,153 @@
+package conf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// longDurationUnits matches the day and week components that
+// time.ParseDuration does not understand.
+var longDurationUnits = regexp.MustCompile(`(\d+(?:\.\d+)?)([dw])`)
+
+// SetExtendedDurations enables the "d" (24h) and "w" (7d) units in duration
+// values, alone or combined with the standard ones as in "1w2d12h".
+func (c *Config) SetExtendedDurations(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.extendedDurations = enabled
+	c.publishLocked()
+}
+
+// parseExtendedDuration rewrites day and week components into hours and
+// parses the result with time.ParseDuration, which sums repeated units.
+func parseExtendedDuration(s string) (time.Duration, bool) {
+	expanded := longDurationUnits.ReplaceAllStringFunc(s, func(part string) string {
+		m := longDurationUnits.FindStringSubmatch(part)
+		n, _ := strconv.ParseFloat(m[1], 64)
+		hours := n * 24
+		if m[2] == "w" {
+			hours *= 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+	d, err := time.ParseDuration(expanded)
+	return d, err == nil
+}
+
+// GetDurationSlice returns the []time.Duration value for the key, such as a
+// retry schedule written as [1s, 5s, 30s] or "1s,5s,30s".
+func (c *Config) GetDurationSlice(key string) []time.Duration {
+	res, err := c.GetDurationSliceE(key)
+	c.noteCoercion(err)
+	if res == nil {
+		return []time.Duration{}
+	}
+	return res
+}
+
+// GetDurationSliceE returns the []time.Duration value for the key, or an
+// error when the key is missing or any element cannot be converted. Elements
+// are parsed like GetDuration values, so strings and numbers of nanoseconds
+// can be mixed.
+func (c *Config) GetDurationSliceE(key string) ([]time.Duration, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res, ok := s.toDurationSlice(v)
+	if !ok && v != nil {
+		return nil, &ConversionError{Key: key, Value: v, Target: "[]time.Duration"}
+	}
+	return res, nil
+}
+
+// GetStringMapDuration returns the map[string]time.Duration value for the
+// key, such as per-endpoint timeouts.
+func (c *Config) GetStringMapDuration(key string) map[string]time.Duration {
+	res, err := c.GetStringMapDurationE(key)
+	c.noteCoercion(err)
+	if err != nil {
+		return map[string]time.Duration{}
+	}
+	return res
+}
+
+// GetStringMapDurationE returns the map[string]time.Duration value for the
+// key, or an error when the key is missing, its value is not a map or any
+// entry cannot be converted.
+func (c *Config) GetStringMapDurationE(key string) (map[string]time.Duration, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	res, ok := s.toStringMapDuration(v)
+	if !ok && v != nil {
+		return nil, &ConversionError{Key: key, Value: v, Target: "map[string]time.Duration"}
+	}
+	return res, nil
+}
+
+func (o parseOptions) toDurationSlice(v any) ([]time.Duration, bool) {
+	var items []any
+	switch val := v.(type) {
+	case []time.Duration:
+		return append([]time.Duration(nil), val...), true
+	case []any:
+		items = val
+	case []string, string:
+		for _, item := range toStringSlice(val) {
+			items = append(items, item)
+		}
+	default:
+		return nil, false
+	}
+	result := make([]time.Duration, 0, len(items))
+	for _, item := range items {
+		d, ok := o.toDuration(item)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, d)
+	}
+	return result, true
+}
+
+func (o parseOptions) toStringMapDuration(v any) (map[string]time.Duration, bool) {
+	var entries map[string]any
+	switch val := v.(type) {
+	case map[string]time.Duration:
+		res := make(map[string]time.Duration, len(val))
+		for k, d := range val {
+			res[k] = d
+		}
+		return res, true
+	case map[string]any:
+		entries = val
+	case map[string]string:
+		entries = make(map[string]any, len(val))
+		for k, item := range val {
+			entries[k] = item
+		}
+	case map[any]any:
+		entries = make(map[string]any, len(val))
+		for k, item := range val {
+			entries[fmt.Sprint(k)] = item
+		}
+	default:
+		return nil, false
+	}
+	res := make(map[string]time.Duration, len(entries))
+	for k, item := range entries {
+		d, ok := o.toDuration(item)
+		if !ok {
+			return nil, false
+		}
+		res[k] = d
+	}
+	return res, true
+}
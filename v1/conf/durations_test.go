@@ -0,0 +1,67 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtendedDurations(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"retention": "2w",
+		"uptime":    "1d12h30m",
+		"grace":     "1.5d",
+		"timeout":   "90s",
+	})
+
+	if _, err := c.GetDurationE("retention"); err == nil {
+		t.Fatalf("expected week unit to be rejected unless enabled")
+	}
+
+	c.SetExtendedDurations(true)
+	tests := map[string]time.Duration{
+		"retention": 14 * 24 * time.Hour,
+		"uptime":    36*time.Hour + 30*time.Minute,
+		"grace":     36 * time.Hour,
+		"timeout":   90 * time.Second,
+	}
+	for key, want := range tests {
+		if got := c.GetDuration(key); got != want {
+			t.Fatalf("expected %s=%s, got %s", key, want, got)
+		}
+	}
+}
+
+func TestDurationSliceAndMap(t *testing.T) {
+	c := New()
+	c.SetConfigType("yaml")
+	data := "retry: [1s, 5s, 30s]\ntimeouts:\n  search: 250ms\n  upload: 2m\nbad: [1s, soon]\n"
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+	if got, err := c.GetDurationSliceE("retry"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v, %v", want, got, err)
+	}
+	c.Set("backoff", "100ms, 1s")
+	if got := c.GetDurationSlice("backoff"); !reflect.DeepEqual(got, []time.Duration{100 * time.Millisecond, time.Second}) {
+		t.Fatalf("expected parsed comma separated durations, got %v", got)
+	}
+	if _, err := c.GetDurationSliceE("bad"); err == nil {
+		t.Fatalf("expected an error for an invalid element")
+	}
+
+	timeouts, err := c.GetStringMapDurationE("timeouts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeouts["search"] != 250*time.Millisecond || timeouts["upload"] != 2*time.Minute {
+		t.Fatalf("unexpected timeouts %v", timeouts)
+	}
+	if got := c.GetStringMapDuration("retry"); len(got) != 0 {
+		t.Fatalf("expected an empty map for a list, got %v", got)
+	}
+}
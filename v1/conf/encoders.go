@@ -0,0 +1,130 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	ini "gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder defines the behavior for serializing a configuration map.
+type Encoder interface {
+	Encode(values map[string]any) ([]byte, error)
+}
+
+// JSONEncoder implements Encoder for indented JSON documents.
+type JSONEncoder struct{}
+
+// Encode serializes values as indented JSON.
+func (JSONEncoder) Encode(values map[string]any) ([]byte, error) {
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// YAMLEncoder implements Encoder for YAML documents.
+type YAMLEncoder struct{}
+
+// Encode serializes values as YAML.
+func (YAMLEncoder) Encode(values map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(numberLiterals(values, yamlNumber)); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// TOMLEncoder implements Encoder for TOML documents.
+type TOMLEncoder struct{}
+
+// Encode serializes values as TOML.
+func (TOMLEncoder) Encode(values map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(numberLiterals(values, newTOMLNumber)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// INIEncoder implements Encoder for INI documents. INILoader only reads the
+// default section, so nested keys are written there with dotted names.
+type INIEncoder struct{}
+
+// Encode serializes values as INI.
+func (INIEncoder) Encode(values map[string]any) ([]byte, error) {
+	flat := make(map[string]any)
+	flattenInto("", values, flat, defaultKeyDelimiter)
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	file := ini.Empty()
+	section := file.Section("")
+	for _, key := range keys {
+		value := flat[key]
+		if items, ok := value.([]any); ok {
+			value = strings.Join(toStringSlice(items), ",")
+		}
+		if _, err := section.NewKey(key, stringify(value)); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := file.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func defaultEncoders() map[string]Encoder {
+	return map[string]Encoder{
+		"json": JSONEncoder{},
+		"yaml": YAMLEncoder{},
+		"yml":  YAMLEncoder{},
+		"toml": TOMLEncoder{},
+		"ini":  INIEncoder{},
+	}
+}
+
+// RegisterEncoder registers or replaces the encoder responsible for the
+// provided extension, normalized the same way as in RegisterLoader.
+func (c *Config) RegisterEncoder(ext string, encoder Encoder) {
+	normalized := normalizeExt(ext)
+	if normalized == "" || encoder == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.encoders == nil {
+		c.encoders = make(map[string]Encoder)
+	}
+	c.encoders[normalized] = encoder
+}
+
+// MarshalTo serializes the effective configuration, merged from every
+// source, with the encoder registered for format.
+func (c *Config) MarshalTo(format string) ([]byte, error) {
+	c.mu.RLock()
+	encoder, ok := c.encoders[c.formatLocked(format)]
+	c.mu.RUnlock()
+	if !ok || encoder == nil {
+		return nil, ErrUnsupportedFormat
+	}
+	return encoder.Encode(c.load().settings())
+}
+
+func normalizeExt(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(ext, "."))
+}
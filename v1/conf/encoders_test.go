@@ -0,0 +1,34 @@
+package conf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMarshalToRoundTrip(t *testing.T) {
+	c := New()
+	c.SetDefault("log.level", "info")
+	c.MergeConfigMap(map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 8080},
+	})
+
+	for _, format := range []string{"json", "yaml", ".TOML", "ini"} {
+		data, err := c.MarshalTo(format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		other := New()
+		other.SetConfigType(strings.TrimPrefix(strings.ToLower(format), "."))
+		if err := other.ReadConfig(bytes.NewReader(data)); err != nil {
+			t.Fatalf("%s: failed to read back %q: %v", format, data, err)
+		}
+		if other.GetInt("server.port") != 8080 || other.GetString("log.level") != "info" {
+			t.Fatalf("%s: unexpected round trip of %q", format, data)
+		}
+	}
+
+	if _, err := c.MarshalTo("xml"); err == nil {
+		t.Fatalf("expected error for format without encoder")
+	}
+}
@@ -0,0 +1,139 @@
+package conf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Cipher encrypts and decrypts the sections registered with EncryptSection.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns a Cipher using AES-GCM with a random nonce prepended to
+// every ciphertext. The key must be 16, 24 or 32 bytes long.
+func NewAESGCM(key []byte) (Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return aesGCM{aead: aead}, nil
+}
+
+func (a aesGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return a.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (a aesGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	size := a.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("ciphertext too short")
+	}
+	return a.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+const (
+	encryptedPrefix = "ENC["
+	encryptedSuffix = "]"
+)
+
+// EncryptSection stores the subtree below key encrypted on disk, as a single
+// "ENC[...]" string holding the ciphertext of its JSON encoding, while the
+// rest of the file stays in plain text. The section is decrypted whenever a
+// file or payload is decoded, and encrypted again by WriteConfig and
+// WriteConfigAs. A section still in plain text is read as is, so it is
+// encrypted on the next write. Keys below the section are treated as secret.
+func (c *Config) EncryptSection(key string, ci Cipher) {
+	if key == "" || ci == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.encrypted == nil {
+		c.encrypted = make(map[string]Cipher)
+	}
+	c.encrypted[key] = ci
+	if !containsString(c.secrets, key) {
+		c.secrets = append(c.secrets, key)
+	}
+}
+
+func (c *Config) encryptedSectionsLocked() []string {
+	keys := make([]string, 0, len(c.encrypted))
+	for key := range c.encrypted {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// decryptSectionsLocked replaces the encrypted sections of values, a freshly
+// decoded map, with their plain text.
+func (c *Config) decryptSectionsLocked(values map[string]any) error {
+	for _, key := range c.encryptedSectionsLocked() {
+		v, ok := fetchValue(values, key, c.delimLocked())
+		if !ok {
+			continue
+		}
+		text, ok := v.(string)
+		if !ok || !strings.HasPrefix(text, encryptedPrefix) || !strings.HasSuffix(text, encryptedSuffix) {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(text[len(encryptedPrefix) : len(text)-len(encryptedSuffix)])
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", key, err)
+		}
+		plain, err := c.encrypted[key].Decrypt(data)
+		if err != nil {
+			return fmt.Errorf("decrypt %s: %w", key, err)
+		}
+		var section any
+		if err := json.Unmarshal(plain, &section); err != nil {
+			return fmt.Errorf("decrypt %s: %w", key, err)
+		}
+		setPath(values, strings.Split(key, c.delimLocked()), normalizeValue(section))
+	}
+	return nil
+}
+
+// encryptSectionsLocked replaces the sections of values, a nested map about
+// to be written, with their ciphertext.
+func (c *Config) encryptSectionsLocked(values map[string]any) error {
+	for _, key := range c.encryptedSectionsLocked() {
+		v, ok := fetchValue(values, key, c.delimLocked())
+		if !ok {
+			continue
+		}
+		plain, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", key, err)
+		}
+		data, err := c.encrypted[key].Encrypt(plain)
+		if err != nil {
+			return fmt.Errorf("encrypt %s: %w", key, err)
+		}
+		text := encryptedPrefix + base64.StdEncoding.EncodeToString(data) + encryptedSuffix
+		setPath(values, strings.Split(key, c.delimLocked()), text)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package conf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptSection(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	ci, err := NewAESGCM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	plain := "name: demo\nvault:\n  password: hunter2\n  port: 8200\n"
+	if err := os.WriteFile(path, []byte(plain), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	c.EncryptSection("vault", ci)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read plain text section: %v", err)
+	}
+	if err := c.WriteConfig(); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || !strings.Contains(string(data), "vault: ENC[") {
+		t.Fatalf("expected vault section to be encrypted, got\n%s", data)
+	}
+	if !strings.Contains(string(data), "name: demo") {
+		t.Fatalf("expected the rest of the file in plain text, got\n%s", data)
+	}
+	if !c.IsSecret("vault.port") {
+		t.Fatalf("expected keys of encrypted sections to be secret")
+	}
+
+	other := New()
+	other.SetConfigFile(path)
+	other.EncryptSection("vault", ci)
+	if err := other.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read encrypted section: %v", err)
+	}
+	if other.GetString("vault.password") != "hunter2" || other.GetInt("vault.port") != 8200 {
+		t.Fatalf("expected decrypted values, got %v", other.AllSettingsFlat())
+	}
+
+	wrong, _ := NewAESGCM(bytes.Repeat([]byte{8}, 32))
+	other.EncryptSection("vault", wrong)
+	if err := other.ReadInConfig(); err == nil {
+		t.Fatalf("expected error decrypting with the wrong key")
+	}
+}
@@ -0,0 +1,42 @@
+package conf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnumError reports a value that is not one of the allowed values for a key.
+type EnumError struct {
+	Key     string
+	Value   string
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("conf: invalid value %q for %q, allowed values: %s", e.Value, e.Key, strings.Join(e.Allowed, ", "))
+}
+
+// GetEnum returns the string value for the key after checking that it is one
+// of the allowed values. Values are compared case-sensitively.
+func (c *Config) GetEnum(key string, allowed ...string) (string, error) {
+	return c.getEnum(key, allowed, false)
+}
+
+// GetEnumFold is like GetEnum but compares values case-insensitively and
+// returns the matching allowed value, so "INFO" yields "info".
+func (c *Config) GetEnumFold(key string, allowed ...string) (string, error) {
+	return c.getEnum(key, allowed, true)
+}
+
+func (c *Config) getEnum(key string, allowed []string, fold bool) (string, error) {
+	value, err := c.GetStringE(key)
+	if err != nil {
+		return "", err
+	}
+	for _, option := range allowed {
+		if option == value || (fold && strings.EqualFold(option, value)) {
+			return option, nil
+		}
+	}
+	return "", &EnumError{Key: key, Value: value, Allowed: append([]string(nil), allowed...)}
+}
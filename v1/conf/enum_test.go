@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetEnum(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"log_level": "INFO", "mode": "fast"})
+
+	levels := []string{"debug", "info", "warn", "error"}
+	if _, err := c.GetEnum("log_level", levels...); err == nil {
+		t.Fatalf("expected case-sensitive match to fail")
+	}
+	got, err := c.GetEnumFold("log_level", levels...)
+	if err != nil || got != "info" {
+		t.Fatalf("expected info, got %q, %v", got, err)
+	}
+
+	_, err = c.GetEnum("mode", "safe", "slow")
+	var eerr *EnumError
+	if !errors.As(err, &eerr) || eerr.Value != "fast" {
+		t.Fatalf("expected EnumError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "safe, slow") {
+		t.Fatalf("expected allowed values in error, got %q", err.Error())
+	}
+	if _, err := c.GetEnum("missing", "a"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package conf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvBindings returns every known key, including declared ones, mapped to
+// the environment variable that can override it: the name passed to BindEnv
+// or, otherwise, the one derived from the key and the env prefix.
+func (c *Config) EnvBindings() map[string]string {
+	s := c.load()
+	out := make(map[string]string)
+	for _, key := range s.keys() {
+		out[key] = s.envName(key)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for key := range c.meta {
+		out[key] = s.envName(key)
+	}
+	return out
+}
+
+// SetEnvPrefixes sets the prefixes of the environment variables, checked in
+// priority order, so renamed products and shared base images keep working
+// during a transition: with SetEnvPrefixes("MYAPP", "APP"), "db.host" is read
+// from MYAPP_DB_HOST, then from APP_DB_HOST. The first prefix is the one set
+// by SetEnvPrefix and reported by EnvBindings; an empty prefix stands for the
+// unprefixed names. Variables bound with BindEnv are not affected.
+func (c *Config) SetEnvPrefixes(prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.envPrefix, c.envFallback = "", nil
+	if len(prefixes) > 0 {
+		c.envPrefix = prefixes[0]
+		c.envFallback = append([]string(nil), prefixes[1:]...)
+	}
+	c.publishLocked()
+}
+
+// AllowUnprefixedEnv lets the keys matching the patterns, and everything
+// below them, be read from their unprefixed environment variable as well,
+// checked after every prefix: with AllowUnprefixedEnv("http.proxy"),
+// HTTP_PROXY is honored when APP_HTTP_PROXY is not set. Patterns use the same
+// syntax as MarkSecret.
+func (c *Config) AllowUnprefixedEnv(patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	for _, pattern := range patterns {
+		if pattern != "" && !containsString(c.envBare, pattern) {
+			c.envBare = append(c.envBare, pattern)
+		}
+	}
+	c.publishLocked()
+}
+
+// SetStructuredEnv enables parsing of environment values holding JSON or
+// YAML documents, so APP_SERVERS='["a","b"]' yields a list and
+// APP_DB='{"host": "x"}' a map. Values that do not decode to a list or a map
+// are kept as plain strings.
+func (c *Config) SetStructuredEnv(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.structuredEnv = enabled
+	c.publishLocked()
+}
+
+func parseStructuredEnv(value string) any {
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return value
+	}
+	switch parsed.(type) {
+	case map[string]any, []any:
+		return normalizeValue(parsed)
+	}
+	return value
+}
+
+// BindEnvFromStruct registers env bindings from the `env` tags of the struct
+// pointed to by v, following the caarlos0/env conventions: `env:"PORT"`
+// binds the field, `envPrefix:"DB_"` prefixes the variables of a nested
+// struct, `envDefault:"8080"` sets the default and the "required" option
+// marks the key as required. Keys are derived as in Unmarshal, from the
+// `mapstructure` tag or the lower case field name.
+func (c *Config) BindEnvFromStruct(v any) error {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return errors.New("conf: BindEnvFromStruct expects a struct or a pointer to one")
+	}
+	c.bindStructEnv(t, "", "", make(map[reflect.Type]bool))
+	return nil
+}
+
+// bindStructEnv binds the fields of t. Struct types already being walked,
+// in visiting, are skipped, so self-referencing types such as linked list
+// nodes terminate.
+func (c *Config) bindStructEnv(t reflect.Type, keyPrefix, envPrefix string, visiting map[reflect.Type]bool) {
+	visiting[t] = true
+	defer delete(visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := joinPath(keyPrefix, name, c.load().delim)
+		if field.Anonymous && strings.Contains(opts, "squash") {
+			key = keyPrefix
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		env, ok := field.Tag.Lookup("env")
+		if !ok && ft.Kind() == reflect.Struct {
+			if !visiting[ft] {
+				c.bindStructEnv(ft, key, envPrefix+field.Tag.Get("envPrefix"), visiting)
+			}
+			continue
+		}
+		envName, envOpts, _ := strings.Cut(env, ",")
+		if envName == "" || key == "" {
+			continue
+		}
+		c.BindEnv(key, envPrefix+envName)
+		if def, ok := field.Tag.Lookup("envDefault"); ok {
+			c.SetDefault(key, def)
+		}
+		if containsString(strings.Split(envOpts, ","), "required") {
+			c.Require(key)
+		}
+	}
+}
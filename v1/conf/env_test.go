@@ -0,0 +1,144 @@
+package conf
+
+import "testing"
+
+func TestEnvBindings(t *testing.T) {
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.SetDefault("server.port", 8080)
+	c.BindEnv("db.url", "DATABASE_URL")
+	c.Declare("log.level", Meta{Type: TypeString})
+
+	got := c.EnvBindings()
+	want := map[string]string{
+		"server.port": "APP_SERVER_PORT",
+		"db.url":      "DATABASE_URL",
+		"log.level":   "APP_LOG_LEVEL",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for key, env := range want {
+		if got[key] != env {
+			t.Fatalf("expected %s for %s, got %q", env, key, got[key])
+		}
+	}
+}
+
+func TestStructuredEnv(t *testing.T) {
+	t.Setenv("APP_SERVERS", `["a", "b"]`)
+	t.Setenv("APP_DB", "{host: db.local, port: 5432}")
+	t.Setenv("APP_LABEL", "[not json")
+
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.SetDefault("servers", []string{})
+	c.SetDefault("db", map[string]any{})
+	c.SetDefault("label", "")
+	if got := c.GetString("servers"); got != `["a", "b"]` {
+		t.Fatalf("expected raw env value when disabled, got %q", got)
+	}
+
+	c.SetStructuredEnv(true)
+	if got := c.GetStringSlice("servers"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("expected list from env, got %v", got)
+	}
+	var db struct {
+		Host string
+		Port int
+	}
+	if err := c.Unmarshal("db", &db); err != nil {
+		t.Fatal(err)
+	}
+	if db.Host != "db.local" || db.Port != 5432 {
+		t.Fatalf("expected map from env, got %+v", db)
+	}
+	if got := c.GetString("label"); got != "[not json" {
+		t.Fatalf("expected invalid documents to stay strings, got %q", got)
+	}
+}
+
+func TestBindEnvFromStruct(t *testing.T) {
+	type Database struct {
+		Host string `env:"HOST,required"`
+		Port int    `env:"PORT" envDefault:"5432"`
+	}
+	type AppConfig struct {
+		Name     string   `env:"APP_NAME"`
+		LogLevel string   `mapstructure:"log_level" env:"LOG_LEVEL" envDefault:"info"`
+		DB       Database `envPrefix:"DB_"`
+		Ignored  string
+	}
+	t.Setenv("APP_NAME", "demo")
+	t.Setenv("DB_HOST", "db.local")
+
+	c := New()
+	if err := c.BindEnvFromStruct(&AppConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	got := c.EnvBindings()
+	want := map[string]string{
+		"name":      "APP_NAME",
+		"log_level": "LOG_LEVEL",
+		"db.host":   "DB_HOST",
+		"db.port":   "DB_PORT",
+	}
+	for key, env := range want {
+		if got[key] != env {
+			t.Fatalf("expected %s for %s, got %q", env, key, got[key])
+		}
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected required key to be set, got %v", err)
+	}
+
+	if c.GetString("name") != "demo" || c.GetInt("db.port") != 5432 || c.GetString("log_level") != "info" {
+		t.Fatalf("unexpected values %v", c.AllSettingsFlat())
+	}
+	if err := c.BindEnvFromStruct(42); err == nil {
+		t.Fatalf("expected error for non struct value")
+	}
+}
+
+func TestBindEnvFromStructCycle(t *testing.T) {
+	type Node struct {
+		Name string `env:"NAME"`
+		Next *Node
+	}
+	t.Setenv("NAME", "head")
+	c := New()
+	if err := c.BindEnvFromStruct(&Node{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("name"); got != "head" {
+		t.Fatalf("expected name head, got %q", got)
+	}
+}
+
+func TestSetEnvPrefixes(t *testing.T) {
+	t.Setenv("MYAPP_DB_HOST", "new")
+	t.Setenv("APP_DB_HOST", "old")
+	t.Setenv("APP_DB_PORT", "5432")
+	t.Setenv("HTTP_PROXY", "proxy:3128")
+	t.Setenv("DB_USER", "ignored")
+	c := New()
+	c.SetEnvPrefixes("MYAPP", "APP")
+	c.AllowUnprefixedEnv("http.proxy")
+	c.SetDefault("db.host", "localhost")
+
+	if got := c.GetString("db.host"); got != "new" {
+		t.Fatalf("expected the first prefix to win, got %q", got)
+	}
+	if got := c.GetInt("db.port"); got != 5432 {
+		t.Fatalf("expected the fallback prefix to be used, got %d", got)
+	}
+	if got := c.GetString("http.proxy"); got != "proxy:3128" {
+		t.Fatalf("expected the unprefixed variable for an allowed key, got %q", got)
+	}
+	if c.IsSet("db.user") {
+		t.Fatalf("expected unprefixed variables to be ignored for other keys")
+	}
+	if got := c.EnvBindings()["db.host"]; got != "MYAPP_DB_HOST" {
+		t.Fatalf("expected the primary name to be reported, got %q", got)
+	}
+}
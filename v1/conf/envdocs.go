@@ -0,0 +1,99 @@
+package conf
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// EnvDoc documents the environment variable overriding a key.
+type EnvDoc struct {
+	Key         string `json:"key"`
+	Env         string `json:"env"`
+	Type        string `json:"type"`
+	Default     any    `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// ExportEnvDocs returns a JSON array documenting every known key, sorted, with
+// the environment variable overriding it, its type, default and description,
+// so deployment tooling can generate env blocks. The type is the declared one
+// or, otherwise, inferred from the default; the description is the declared
+// one or the comment set with Describe. Durations are rendered as strings,
+// such as "5s", and defaults of secret keys are left out.
+func (c *Config) ExportEnvDocs() ([]byte, error) {
+	s := c.load()
+	keys := s.keys()
+	defaults := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if v, ok := s.getDefault(key); ok {
+			defaults[key] = v
+		}
+	}
+
+	c.mu.RLock()
+	for key := range c.meta {
+		if !containsString(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	docs := make([]EnvDoc, 0, len(keys))
+	for _, key := range keys {
+		meta := c.meta[key]
+		doc := EnvDoc{
+			Key:         key,
+			Env:         s.envName(key),
+			Type:        meta.Type.String(),
+			Description: meta.Desc,
+			Required:    containsString(c.required, key),
+			Secret:      c.isSecretLocked(key),
+		}
+		if doc.Description == "" {
+			doc.Description = c.comments[key]
+		}
+		def, ok := defaults[key]
+		if meta.Type == TypeAny && ok {
+			doc.Type = typeOf(def).String()
+		}
+		if d, isDuration := def.(time.Duration); isDuration {
+			def = d.String()
+		}
+		if ok && !doc.Secret {
+			doc.Default = def
+		}
+		docs = append(docs, doc)
+	}
+	c.mu.RUnlock()
+	return json.MarshalIndent(docs, "", "  ")
+}
+
+// typeOf infers the Type of a value.
+func typeOf(v any) Type {
+	switch val := v.(type) {
+	case json.Number:
+		if isIntegerNumber(val) {
+			return TypeInt
+		}
+		return TypeFloat
+	case string:
+		return TypeString
+	case bool:
+		return TypeBool
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return TypeInt
+	case float32, float64:
+		return TypeFloat
+	case time.Duration:
+		return TypeDuration
+	case []string:
+		return TypeStringSlice
+	case []int:
+		return TypeIntSlice
+	case map[string]any, map[string]string:
+		return TypeMap
+	}
+	return TypeAny
+}
@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestExportEnvDocs(t *testing.T) {
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.SetDefault("server.port", 8080)
+	c.SetDefault("server.timeout", 5*time.Second)
+	c.Describe("server.port", "port to listen on")
+	c.Declare("db.password", Meta{Type: TypeString, Desc: "database password", Required: true, Default: "changeme"})
+	c.BindEnv("db.user", "DATABASE_USER")
+
+	data, err := c.ExportEnvDocs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var docs []EnvDoc
+	if err := json.Unmarshal(data, &docs); err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 4 {
+		t.Fatalf("expected 4 keys, got %s", data)
+	}
+	want := map[string]EnvDoc{
+		"db.password":    {Key: "db.password", Env: "APP_DB_PASSWORD", Type: "string", Description: "database password", Required: true, Secret: true},
+		"db.user":        {Key: "db.user", Env: "DATABASE_USER", Type: "any"},
+		"server.port":    {Key: "server.port", Env: "APP_SERVER_PORT", Type: "int", Default: float64(8080), Description: "port to listen on"},
+		"server.timeout": {Key: "server.timeout", Env: "APP_SERVER_TIMEOUT", Type: "duration", Default: "5s"},
+	}
+	for _, doc := range docs {
+		if doc != want[doc.Key] {
+			t.Fatalf("expected %+v, got %+v", want[doc.Key], doc)
+		}
+	}
+	if docs[0].Key != "db.password" || docs[3].Key != "server.timeout" {
+		t.Fatalf("expected keys sorted, got %s", data)
+	}
+}
@@ -0,0 +1,104 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WriteExample writes an example configuration file to path containing
+// every declared key and every key with a default. Each key is preceded by
+// comments built from its metadata (description, type, allowed values) and
+// holds its default, or its example when no default exists. Secret keys are
+// left empty. When format is empty it is inferred from the path extension.
+func (c *Config) WriteExample(path, format string) error {
+	if format == "" {
+		format = filepath.Ext(path)
+	}
+	s := c.load()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	flat := make(map[string]any)
+	flattenInto("", s.defaults, flat, s.delim)
+	for key := range c.meta {
+		if _, ok := flat[key]; !ok {
+			flat[key] = nil
+		}
+	}
+	values := make(map[string]any)
+	comments := make(map[string][]string)
+	for key, value := range flat {
+		meta := c.meta[key]
+		if value == nil {
+			value = meta.Example
+		}
+		if value == nil || c.isSecretLocked(key) {
+			value = meta.Type.zero()
+		}
+		setPath(values, strings.Split(key, s.delim), value)
+		comments[key] = c.commentsLocked(key)
+	}
+	for key, comment := range c.comments {
+		if _, ok := comments[key]; !ok {
+			comments[key] = strings.Split(comment, "\n")
+		}
+	}
+
+	data, err := c.encodeCommentedLocked(format, values, comments)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// comments returns the lines describing the metadata in an example file.
+func (m Meta) comments() []string {
+	var lines []string
+	if m.Desc != "" {
+		lines = append(lines, strings.Split(m.Desc, "\n")...)
+	}
+	var attrs []string
+	if m.Type != TypeAny {
+		attrs = append(attrs, "type: "+m.Type.String())
+	}
+	if m.Required {
+		attrs = append(attrs, "required")
+	}
+	if len(m.Enum) > 0 {
+		allowed := make([]string, len(m.Enum))
+		for i, v := range m.Enum {
+			allowed[i] = fmt.Sprint(v)
+		}
+		attrs = append(attrs, "one of: "+strings.Join(allowed, ", "))
+	}
+	if m.Example != nil && m.Default != nil {
+		attrs = append(attrs, "example: "+fmt.Sprint(m.Example))
+	}
+	if len(attrs) > 0 {
+		lines = append(lines, strings.Join(attrs, "; "))
+	}
+	return lines
+}
+
+// zero returns the placeholder written for a key of type t without a value.
+func (t Type) zero() any {
+	switch t {
+	case TypeInt:
+		return 0
+	case TypeBool:
+		return false
+	case TypeFloat:
+		return 0.0
+	case TypeDuration:
+		return time.Duration(0)
+	case TypeStringSlice, TypeIntSlice:
+		return []any{}
+	case TypeMap:
+		return map[string]any{}
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteExample(t *testing.T) {
+	c := New()
+	c.Declare("server.port", Meta{Type: TypeInt, Default: 8080, Desc: "Port to listen on."})
+	c.Declare("log.level", Meta{Type: TypeString, Default: "info", Enum: []any{"debug", "info"}})
+	c.Declare("db.password", Meta{Type: TypeString, Required: true, Default: "hunter2"})
+	c.Declare("cache.ttl", Meta{Type: TypeDuration, Example: "5m"})
+	c.SetDefault("name", "demo")
+
+	dir := t.TempDir()
+	for _, format := range []string{"yaml", "toml", "ini"} {
+		path := filepath.Join(dir, "example."+format)
+		if err := c.WriteExample(path, ""); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		text := string(data)
+		for _, want := range []string{"Port to listen on.", "type: int", "one of: debug, info", "required"} {
+			if !strings.Contains(text, want) {
+				t.Fatalf("%s: expected %q in\n%s", format, want, text)
+			}
+		}
+		if strings.Contains(text, "hunter2") {
+			t.Fatalf("%s: expected secret default to be omitted:\n%s", format, text)
+		}
+
+		other := New()
+		other.SetConfigFile(path)
+		if err := other.ReadInConfig(); err != nil {
+			t.Fatalf("%s: failed to read back:\n%s\n%v", format, text, err)
+		}
+		if other.GetInt("server.port") != 8080 || other.GetString("name") != "demo" {
+			t.Fatalf("%s: unexpected values in\n%s", format, text)
+		}
+		if other.GetDuration("cache.ttl") != 5*time.Minute {
+			t.Fatalf("%s: expected example value for cache.ttl in\n%s", format, text)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package conf
+
+import "os"
+
+// GetStringExpand returns the string value for the key with "$VAR" and
+// "${VAR}" references replaced through mapping, or through os.Getenv when
+// mapping is nil. Unknown variables expand to the empty string, as with
+// os.Expand.
+//
+//	cmd := cfg.GetStringExpand("hooks.post_deploy", nil) // "notify --host $HOSTNAME"
+func (c *Config) GetStringExpand(key string, mapping func(string) string) string {
+	if mapping == nil {
+		mapping = os.Getenv
+	}
+	return os.Expand(c.GetString(key), mapping)
+}
@@ -0,0 +1,19 @@
+package conf
+
+import "testing"
+
+func TestGetStringExpand(t *testing.T) {
+	t.Setenv("APP_HOME", "/opt/app")
+	c := New()
+	c.SetDefault("data_dir", "${APP_HOME}/data")
+	c.SetDefault("command", "deploy --region $REGION --tier ${TIER}")
+
+	if got := c.GetStringExpand("data_dir", nil); got != "/opt/app/data" {
+		t.Fatalf("expected environment expansion, got %q", got)
+	}
+	vars := map[string]string{"REGION": "eu-west-1", "TIER": "web"}
+	got := c.GetStringExpand("command", func(name string) string { return vars[name] })
+	if got != "deploy --region eu-west-1 --tier web" {
+		t.Fatalf("expected custom mapping, got %q", got)
+	}
+}
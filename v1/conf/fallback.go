@@ -0,0 +1,101 @@
+package conf
+
+import "time"
+
+// GetStringDefault returns the string value for the key, or fallback when the
+// key cannot be resolved from any source.
+func (c *Config) GetStringDefault(key, fallback string) string {
+	return GetOr(c, key, fallback)
+}
+
+// GetIntDefault returns the int value for the key, or fallback when the key
+// cannot be resolved from any source or its value cannot be converted.
+func (c *Config) GetIntDefault(key string, fallback int) int {
+	return GetOr(c, key, fallback)
+}
+
+// GetBoolDefault returns the boolean value for the key, or fallback when the
+// key cannot be resolved from any source or its value cannot be converted.
+func (c *Config) GetBoolDefault(key string, fallback bool) bool {
+	return GetOr(c, key, fallback)
+}
+
+// GetFloat64Default returns the float64 value for the key, or fallback when
+// the key cannot be resolved from any source or its value cannot be
+// converted.
+func (c *Config) GetFloat64Default(key string, fallback float64) float64 {
+	return GetOr(c, key, fallback)
+}
+
+// GetDurationDefault returns the time.Duration value for the key, or fallback
+// when the key cannot be resolved from any source or its value cannot be
+// converted.
+func (c *Config) GetDurationDefault(key string, fallback time.Duration) time.Duration {
+	return GetOr(c, key, fallback)
+}
+
+// GetStringSliceDefault returns the []string value for the key, or fallback
+// when the key cannot be resolved from any source or its value cannot be
+// converted.
+func (c *Config) GetStringSliceDefault(key string, fallback []string) []string {
+	return GetOr(c, key, fallback)
+}
+
+// GetOr resolves key and converts it to T using the same weak conversions as
+// the typed getters. The fallback is returned when the key is missing or its
+// value cannot be converted.
+//
+//	timeout := conf.GetOr(cfg, "timeout", 30*time.Second)
+func GetOr[T any](c *Config, key string, fallback T) T {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return fallback
+	}
+	if res, ok := convertTo[T](s.parseOptions, v); ok {
+		return res
+	}
+	return fallback
+}
+
+func convertTo[T any](o parseOptions, v any) (T, bool) {
+	var zero T
+	if res, ok := v.(T); ok {
+		return res, true
+	}
+	var (
+		out any
+		ok  bool
+	)
+	switch any(zero).(type) {
+	case string:
+		out, ok = stringify(v), true
+	case int:
+		out, ok = o.toInt(v)
+	case bool:
+		out, ok = o.toBool(v)
+	case float64:
+		out, ok = o.toFloat64(v)
+	case time.Duration:
+		out, ok = o.toDuration(v)
+	case []string:
+		res := toStringSlice(v)
+		out, ok = res, res != nil
+	case []int:
+		out, ok = o.toIntSlice(v)
+	case map[string]any:
+		out, ok = toStringMap(v)
+	case map[string]string:
+		out, ok = toStringMapString(v)
+	default:
+		var res T
+		if err := decode(cloneValue(v), &res); err != nil {
+			return zero, false
+		}
+		return res, true
+	}
+	if !ok {
+		return zero, false
+	}
+	return out.(T), true
+}
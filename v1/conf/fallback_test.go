@@ -0,0 +1,85 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultGetters(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"name":    "app",
+		"port":    9000,
+		"debug":   false,
+		"timeout": "2s",
+	})
+
+	if got := c.GetStringDefault("name", "fallback"); got != "app" {
+		t.Fatalf("expected name app, got %q", got)
+	}
+	if got := c.GetStringDefault("missing", "fallback"); got != "fallback" {
+		t.Fatalf("expected fallback, got %q", got)
+	}
+	if got := c.GetIntDefault("port", 80); got != 9000 {
+		t.Fatalf("expected port 9000, got %d", got)
+	}
+	if got := c.GetIntDefault("missing", 80); got != 80 {
+		t.Fatalf("expected fallback 80, got %d", got)
+	}
+	if got := c.GetBoolDefault("debug", true); got {
+		t.Fatalf("expected explicit false to win over fallback")
+	}
+	if got := c.GetFloat64Default("missing", 0.5); got != 0.5 {
+		t.Fatalf("expected fallback 0.5, got %f", got)
+	}
+	if got := c.GetDurationDefault("timeout", time.Minute); got != 2*time.Second {
+		t.Fatalf("expected timeout 2s, got %s", got)
+	}
+	if got := c.GetStringSliceDefault("missing", []string{"a"}); len(got) != 1 || got[0] != "a" {
+		t.Fatalf("expected fallback slice, got %#v", got)
+	}
+
+	c.MergeConfigMap(map[string]any{"port": "eighty", "debug": "maybe", "timeout": "soon"})
+	if got := c.GetIntDefault("port", 80); got != 80 {
+		t.Fatalf("expected fallback 80 for an unconvertible value, got %d", got)
+	}
+	if got := c.GetBoolDefault("debug", true); !got {
+		t.Fatalf("expected fallback true for an unconvertible value")
+	}
+	if got := c.GetDurationDefault("timeout", time.Minute); got != time.Minute {
+		t.Fatalf("expected fallback 1m for an unconvertible value, got %s", got)
+	}
+}
+
+func TestGetOr(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"timeout": "5s",
+		"port":    "8080",
+		"ratio":   "not-a-number",
+		"limits":  map[string]any{"max": "10"},
+	})
+
+	if got := GetOr(c, "timeout", 30*time.Second); got != 5*time.Second {
+		t.Fatalf("expected timeout 5s, got %s", got)
+	}
+	if got := GetOr(c, "missing", 30*time.Second); got != 30*time.Second {
+		t.Fatalf("expected fallback 30s, got %s", got)
+	}
+	if got := GetOr(c, "port", 80); got != 8080 {
+		t.Fatalf("expected port 8080, got %d", got)
+	}
+	if got := GetOr(c, "ratio", 0.25); got != 0.25 {
+		t.Fatalf("expected fallback for inconvertible value, got %f", got)
+	}
+	if got := GetOr(c, "port", int64(1)); got != 8080 {
+		t.Fatalf("expected weakly decoded int64 8080, got %d", got)
+	}
+
+	type limits struct {
+		Max int `mapstructure:"max"`
+	}
+	if got := GetOr(c, "limits", limits{Max: 1}); got.Max != 10 {
+		t.Fatalf("expected decoded struct with max 10, got %+v", got)
+	}
+}
@@ -0,0 +1,74 @@
+package conf
+
+import "fmt"
+
+// FallbackError is reported through the OnError hooks when the primary config
+// file could not be read or decoded and a fallback file was loaded instead.
+type FallbackError struct {
+	// File is the primary config file, empty when the search found none.
+	File string
+	// Fallback is the file that was loaded in its place.
+	Fallback string
+	// Err is the error returned for the primary file.
+	Err error
+}
+
+func (e *FallbackError) Error() string {
+	file := e.File
+	if file == "" {
+		file = "config file"
+	}
+	return fmt.Sprintf("conf: %s: %v (loaded fallback %s)", file, e.Err, e.Fallback)
+}
+
+func (e *FallbackError) Unwrap() error { return e.Err }
+
+// SetFallbackConfigFile sets the files ReadInConfig tries, in order, when the
+// primary config file is missing or cannot be decoded, typically a
+// last-known-good copy. The first fallback that decodes is loaded and the
+// primary failure is reported to the OnError hooks as a *FallbackError. The
+// primary stays the watched and written file. Validator rejections of the
+// primary do not trigger a fallback.
+func (c *Config) SetFallbackConfigFile(paths ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fallbacks = append([]string(nil), paths...)
+}
+
+// OnError registers fn to receive errors that are otherwise only logged:
+// fallback loads, failed reloads of the watched file and provider failures.
+// It is a shortcut for handling TopicError on the event bus.
+func (c *Config) OnError(fn func(error)) {
+	if fn == nil {
+		return
+	}
+	c.events.Handle(TopicError, func(ev Event) { fn(ev.Err) })
+}
+
+// reportError publishes err on TopicError. It must be called without
+// holding c.mu.
+func (c *Config) reportError(err error) {
+	c.events.Publish(Event{Topic: TopicError, Err: err})
+}
+
+// readFallbackLocked loads the first fallback file that decodes and returns
+// the error describing why it was needed. When no fallback can be read the
+// primary error is returned, together with the error of every fallback in a
+// *MultiError.
+func (c *Config) readFallbackLocked(file string, primary error) (*FallbackError, error) {
+	errs := []error{primary}
+	for _, fallback := range c.fallbacks {
+		c.searched = append(c.searched, fallback)
+		parsed, err := c.readConfigFileLocked(fallback)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fallback %s: %w", fallback, err))
+			continue
+		}
+		if err := c.setValuesLocked(parsed); err != nil {
+			return nil, err
+		}
+		c.fileUsed = fallback
+		return &FallbackError{File: file, Fallback: fallback, Err: primary}, nil
+	}
+	return nil, joinErrors(errs)
+}
@@ -0,0 +1,44 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFallbackConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "app.yaml")
+	good := filepath.Join(dir, "app.last-good.json")
+	if err := os.WriteFile(primary, []byte("port: [broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(good, []byte(`{"port": 8080}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(primary)
+	c.SetFallbackConfigFile(filepath.Join(dir, "missing.yaml"), good)
+	var reported []error
+	c.OnError(func(err error) { reported = append(reported, err) })
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("expected fallback to be loaded, got %v", err)
+	}
+	if c.GetInt("port") != 8080 {
+		t.Fatalf("expected port from fallback, got %d", c.GetInt("port"))
+	}
+	var fe *FallbackError
+	if len(reported) != 1 || !errors.As(reported[0], &fe) || fe.File != primary || fe.Fallback != good {
+		t.Fatalf("expected one fallback error, got %v", reported)
+	}
+
+	os.Remove(good)
+	if err := c.ReadInConfig(); err == nil {
+		t.Fatalf("expected primary error when no fallback can be read")
+	}
+	if c.GetInt("port") != 8080 {
+		t.Fatalf("expected previous values to be kept")
+	}
+}
@@ -0,0 +1,53 @@
+package conf
+
+// fault is a failure injected with InjectFailure.
+type fault struct {
+	err       error
+	remaining int
+}
+
+// InjectFailure makes loads of source fail with err, so that applications can
+// exercise their degraded-config and rollback paths deterministically in
+// tests. Source is HookSourceFile for ReadInConfig, including reloads by the
+// watcher, or a provider name for ReadProviders. The next times loads fail,
+// or every load when times is not positive, until the returned function is
+// called. Injecting a failure for a source replaces the previous one.
+//
+// InjectFailure panics unless the Config was created with
+// WithFaultInjection.
+func (c *Config) InjectFailure(source string, err error, times int) (clear func()) {
+	f := &fault{err: err, remaining: times}
+	c.mu.Lock()
+	if !c.faultInjection {
+		c.mu.Unlock()
+		panic("conf: InjectFailure requires the WithFaultInjection option")
+	}
+	if c.faults == nil {
+		c.faults = make(map[string]*fault)
+	}
+	c.faults[source] = f
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.faults[source] == f {
+			delete(c.faults, source)
+		}
+	}
+}
+
+// takeFaultLocked returns the failure injected for source, if any, and
+// consumes one of its occurrences. The caller must hold c.mu for writing.
+func (c *Config) takeFaultLocked(source string) error {
+	f, ok := c.faults[source]
+	if !ok {
+		return nil
+	}
+	if f.remaining > 0 {
+		f.remaining--
+		if f.remaining == 0 {
+			delete(c.faults, source)
+		}
+	}
+	return f.err
+}
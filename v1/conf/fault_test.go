@@ -0,0 +1,47 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInjectFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	boom := errors.New("disk on fire")
+	c := New(WithFaultInjection())
+	c.SetConfigFile(path)
+	c.InjectFailure(HookSourceFile, boom, 1)
+	if err := c.ReadInConfig(); !errors.Is(err, boom) {
+		t.Fatalf("expected injected failure, got %v", err)
+	}
+	if err := c.ReadInConfig(); err != nil || c.GetInt("port") != 1 {
+		t.Fatalf("expected failure to be consumed, got %v", err)
+	}
+
+	c.AddProvider(staticProvider{"replicas": 3})
+	clear := c.InjectFailure("static", boom, 0)
+	for i := 0; i < 2; i++ {
+		if err := c.ReadProviders(context.Background()); !errors.Is(err, boom) {
+			t.Fatalf("expected provider failure until cleared, got %v", err)
+		}
+	}
+	clear()
+	if err := c.ReadProviders(context.Background()); err != nil || c.GetInt("replicas") != 3 {
+		t.Fatalf("expected provider to load once cleared, got %v", err)
+	}
+}
+
+func TestInjectFailureRequiresOption(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected InjectFailure to panic without WithFaultInjection")
+		}
+	}()
+	New().InjectFailure(HookSourceFile, errors.New("boom"), 1)
+}
@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// FingerprintOption customizes Fingerprint.
+type FingerprintOption func(*fingerprintOptions)
+
+type fingerprintOptions struct {
+	excludeSecrets bool
+}
+
+// FingerprintExcludeSecrets leaves secret keys out of the fingerprint, so it
+// can be logged and compared without depending on credentials.
+func FingerprintExcludeSecrets() FingerprintOption {
+	return func(o *fingerprintOptions) {
+		o.excludeSecrets = true
+	}
+}
+
+// Fingerprint returns a hex encoded SHA-256 hash of the effective
+// configuration. It is deterministic: two configurations with the same
+// effective keys and values have the same fingerprint regardless of where
+// the values came from.
+func (c *Config) Fingerprint(opts ...FingerprintOption) string {
+	var o fingerprintOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	flat := c.load().effective()
+	if o.excludeSecrets {
+		c.mu.RLock()
+		for key := range flat {
+			if c.isSecretLocked(key) {
+				delete(flat, key)
+			}
+		}
+		c.mu.RUnlock()
+	}
+	// encoding/json writes map keys in sorted order, which makes the
+	// encoding canonical for the flattened map.
+	data, err := json.Marshal(flat)
+	if err != nil {
+		data = []byte(formatValue(flat))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
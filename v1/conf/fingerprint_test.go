@@ -0,0 +1,27 @@
+package conf
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	a := New()
+	a.SetDefault("port", 8080)
+	a.MergeConfigMap(map[string]any{"db": map[string]any{"host": "x", "password": "one"}})
+
+	b := New()
+	b.MergeConfigMap(map[string]any{"port": 8080, "db": map[string]any{"password": "one", "host": "x"}})
+
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Fatalf("expected equal effective configs to share a fingerprint")
+	}
+	if len(a.Fingerprint()) != 64 {
+		t.Fatalf("expected hex sha256, got %q", a.Fingerprint())
+	}
+
+	b.MergeConfigMap(map[string]any{"db": map[string]any{"password": "two"}})
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Fatalf("expected secret change to alter the full fingerprint")
+	}
+	if a.Fingerprint(FingerprintExcludeSecrets()) != b.Fingerprint(FingerprintExcludeSecrets()) {
+		t.Fatalf("expected secrets to be excluded from the fingerprint")
+	}
+}
@@ -0,0 +1,68 @@
+package conf
+
+import (
+	"encoding/json"
+	"flag"
+	"time"
+)
+
+// FlagValue returns a flag.Value bound to key, so a config key can be exposed
+// as a command line flag:
+//
+//	flag.Var(cfg.FlagValue("log.level"), "log-level", "log level")
+//
+// Set writes the flag argument to the override layer, as Set does, and
+// String returns the effective value, so the flag shows the configured value
+// as its default. Keys holding a boolean behave as boolean flags. The value
+// also implements the Type method of pflag.Value.
+func (c *Config) FlagValue(key string) flag.Value {
+	return &flagValue{c: c, key: key}
+}
+
+type flagValue struct {
+	c   *Config
+	key string
+}
+
+func (f *flagValue) String() string {
+	if f == nil || f.c == nil {
+		return ""
+	}
+	return f.c.GetString(f.key)
+}
+
+func (f *flagValue) Set(value string) error {
+	return f.c.set(f.key, value)
+}
+
+// IsBoolFlag lets the flag package accept -name without a value for keys
+// holding a boolean.
+func (f *flagValue) IsBoolFlag() bool {
+	return f.Type() == "bool"
+}
+
+// Type names the type of the effective value for pflag.
+func (f *flagValue) Type() string {
+	if f == nil || f.c == nil {
+		return "string"
+	}
+	v, _ := f.c.load().get(f.key)
+	switch val := v.(type) {
+	case json.Number:
+		if isIntegerNumber(val) {
+			return "int"
+		}
+		return "float"
+	case bool:
+		return "bool"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "int"
+	case float32, float64:
+		return "float"
+	case time.Duration:
+		return "duration"
+	case []any, []string:
+		return "stringSlice"
+	}
+	return "string"
+}
@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestFlagValue(t *testing.T) {
+	c := New()
+	c.SetDefault("log.level", "info")
+	c.SetDefault("debug", false)
+	c.AddValidator(func(s Snapshot) error {
+		if s.GetString("log.level") == "trace" {
+			return io.EOF
+		}
+		return nil
+	})
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(c.FlagValue("log.level"), "log-level", "log level")
+	fs.Var(c.FlagValue("debug"), "debug", "debug mode")
+	if got := fs.Lookup("log-level").DefValue; got != "info" {
+		t.Fatalf("expected the effective value as flag default, got %q", got)
+	}
+	if err := fs.Parse([]string{"-log-level", "warn", "-debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("log.level"); got != "warn" {
+		t.Fatalf("expected the flag to override the key, got %q", got)
+	}
+	if !c.GetBool("debug") {
+		t.Fatalf("expected -debug without a value to set the key")
+	}
+	if err := fs.Parse([]string{"-log-level", "trace"}); err == nil {
+		t.Fatalf("expected a rejected value to fail parsing")
+	}
+}
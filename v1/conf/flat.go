@@ -0,0 +1,48 @@
+package conf
+
+import "strings"
+
+// AllKeys returns every known key, flattened with dots and sorted: the keys
+// of the defaults, providers, config file values and overrides and the keys
+// bound to environment variables.
+func (c *Config) AllKeys() []string {
+	return c.load().keys()
+}
+
+// AllSettings returns the effective configuration as a nested map, every key
+// holding the value the getters resolve.
+func (c *Config) AllSettings() map[string]any {
+	return c.load().settings()
+}
+
+// AllSettingsFlat returns the effective configuration as a flat map keyed by
+// dotted paths, such as "server.port". Lists are kept as values.
+func (c *Config) AllSettingsFlat() map[string]any {
+	flat := c.load().effective()
+	for key, v := range flat {
+		flat[key] = cloneValue(v)
+	}
+	return flat
+}
+
+// AllSettingsFlatString is like AllSettingsFlat but converts every value to
+// a string the way GetString does, except lists, which are joined with
+// commas so GetStringSlice can split them back.
+func (c *Config) AllSettingsFlatString() map[string]string {
+	flat := c.load().effective()
+	out := make(map[string]string, len(flat))
+	for key, v := range flat {
+		out[key] = flatString(v)
+	}
+	return out
+}
+
+// flatString converts v to a string as AllSettingsFlatString does.
+func flatString(v any) string {
+	switch v.(type) {
+	case []any, []string:
+		return strings.Join(toStringSlice(v), ",")
+	default:
+		return stringify(v)
+	}
+}
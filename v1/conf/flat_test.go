@@ -0,0 +1,49 @@
+package conf
+
+import "testing"
+
+func TestAllSettingsFlat(t *testing.T) {
+	c := New()
+	c.SetDefault("log.level", "info")
+	c.MergeConfigMap(map[string]any{
+		"server": map[string]any{"port": 8080, "hosts": []any{"a", "b"}},
+	})
+
+	flat := c.AllSettingsFlat()
+	if len(flat) != 3 || flat["server.port"] != 8080 || flat["log.level"] != "info" {
+		t.Fatalf("unexpected flat settings %#v", flat)
+	}
+	if hosts, ok := flat["server.hosts"].([]any); !ok || len(hosts) != 2 {
+		t.Fatalf("expected lists to be kept as values, got %#v", flat["server.hosts"])
+	}
+
+	strs := c.AllSettingsFlatString()
+	if strs["server.port"] != "8080" || strs["server.hosts"] != "a,b" {
+		t.Fatalf("unexpected flat string settings %#v", strs)
+	}
+}
+
+func TestAllKeysAndSettings(t *testing.T) {
+	t.Setenv("APP_USER", "admin")
+	c := New()
+	c.SetDefault("log.level", "info")
+	c.BindEnv("db.user", "APP_USER")
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"host": "file"}})
+	c.Set("db.pool", 10)
+
+	keys := c.AllKeys()
+	want := []string{"db.host", "db.pool", "db.user", "log.level"}
+	if len(keys) != len(want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("expected keys %v, got %v", want, keys)
+		}
+	}
+
+	db, ok := c.AllSettings()["db"].(map[string]any)
+	if !ok || db["host"] != "file" || db["user"] != "admin" || db["pool"] != 10 {
+		t.Fatalf("unexpected nested settings %#v", c.AllSettings())
+	}
+}
@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"io"
+	"os"
+)
+
+// SetFileLocking enables advisory locking of the config file: reads take a
+// shared lock and WriteConfig takes an exclusive one, so that processes
+// sharing a config file and opting in never read half-written contents or
+// interleave their writes. It relies on flock on Unix and LockFileEx on
+// Windows and is a no-op elsewhere.
+func (c *Config) SetFileLocking(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fileLocking = enabled
+}
+
+// readFileLocked reads path, holding a shared lock on it when file locking
+// is enabled.
+func (c *Config) readFileLocked(path string) ([]byte, error) {
+	if !c.fileLocking {
+		return os.ReadFile(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := lockFile(f, false); err != nil {
+		return nil, err
+	}
+	defer unlockFile(f)
+	return io.ReadAll(f)
+}
+
+// writeFileLocked replaces the contents of path with data, holding an
+// exclusive lock on it when file locking is enabled. The file is truncated
+// only once the lock is held.
+func (c *Config) writeFileLocked(path string, data []byte, perm os.FileMode) error {
+	if !c.fileLocking {
+		return os.WriteFile(path, data, perm)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := lockFile(f, true); err != nil {
+		return err
+	}
+	defer unlockFile(f)
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,51 @@
+//go:build unix
+
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLockingBlocksReadsDuringWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Another process holding the write lock.
+	writer, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	if err := lockFile(writer, true); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	c.SetFileLocking(true)
+	done := make(chan error, 1)
+	go func() { done <- c.ReadInConfig() }()
+
+	select {
+	case <-done:
+		t.Fatalf("expected read to wait for the exclusive lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := writer.Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.WriteAt([]byte("port: 2\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+	unlockFile(writer)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if c.GetInt("port") != 2 {
+		t.Fatalf("expected the completed write to be read, got %d", c.GetInt("port"))
+	}
+}
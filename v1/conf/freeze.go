@@ -0,0 +1,50 @@
+package conf
+
+import "errors"
+
+// ErrFrozen is returned by the writers of a Config made read-only by Freeze.
+var ErrFrozen = errors.New("conf: configuration is frozen")
+
+// Freeze makes the configuration read-only, guaranteeing it cannot drift
+// once a long-running service has started. Afterwards every change to the
+// defaults, the config file, the overrides and the provider layer is
+// rejected, as is every change to how keys resolve: ReadInConfig,
+// ReadConfig, ReadProviders, RollbackLast, Abort and SetPrecedence return
+// ErrFrozen, while SetDefault, Set, Unset, MergeConfigMap, Declare, BindEnv,
+// SetEnvPrefix, AutomaticEnv, RegisterAlias, LockKey, PinOnRead,
+// SetCoercionPolicy, Use, AddProvider and the other setters that do not
+// return an error panic with it. A change pending after ApplyStaged is kept,
+// its automatic rollback cancelled. Getters keep working and the file
+// watcher stops applying reloads. Freezing cannot be undone.
+func (c *Config) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frozen = true
+	if c.staged != nil && c.staged.timer != nil {
+		c.staged.timer.Stop()
+	}
+}
+
+// Frozen reports whether Freeze has been called.
+func (c *Config) Frozen() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.frozen
+}
+
+// mustNotBeFrozenLocked panics with ErrFrozen once the configuration is
+// frozen. Setters that cannot return an error use it, as changing a frozen
+// configuration is a programming error. The caller must hold c.mu.
+func (c *Config) mustNotBeFrozenLocked() {
+	if c.frozen {
+		panic(ErrFrozen)
+	}
+}
+
+// checkFrozenLocked returns ErrFrozen once the configuration is frozen.
+func (c *Config) checkFrozenLocked() error {
+	if c.frozen {
+		return ErrFrozen
+	}
+	return nil
+}
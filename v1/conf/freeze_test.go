@@ -0,0 +1,100 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFreeze(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte("port: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetConfigFile(file)
+	c.SetDefault("host", "localhost")
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	c.Freeze()
+	if !c.Frozen() {
+		t.Fatalf("expected config to be frozen")
+	}
+
+	if err := os.WriteFile(file, []byte("port: 9090\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen from ReadInConfig, got %v", err)
+	}
+	writers := map[string]func(){
+		"SetDefault":             func() { c.SetDefault("host", "example.com") },
+		"SetDefaultFunc":         func() { c.SetDefaultFunc("host", func(*Config) any { return "x" }) },
+		"Set":                    func() { c.Set("port", 1) },
+		"Unset":                  func() { c.Unset("port") },
+		"MergeConfigMap":         func() { c.MergeConfigMap(map[string]any{"port": 2}) },
+		"BindEnv":                func() { c.BindEnv("port", "PORT") },
+		"SetEnvPrefix":           func() { c.SetEnvPrefix("APP") },
+		"SetEnvPrefixes":         func() { c.SetEnvPrefixes("APP", "OLD") },
+		"AllowUnprefixedEnv":     func() { c.AllowUnprefixedEnv("port") },
+		"AutomaticEnv":           func() { c.AutomaticEnv() },
+		"RegisterAlias":          func() { c.RegisterAlias("listen", "port") },
+		"SetKeyDelimiter":        func() { c.SetKeyDelimiter("::") },
+		"SetKeysCaseInsensitive": func() { c.SetKeysCaseInsensitive(true) },
+		"Use":                    func() { c.Use(func(key string, next Resolver) (any, bool) { return next(key) }) },
+		"Transform":              func() { c.Transform("host", func(s string) string { return s }) },
+		"AddProvider":            func() { c.AddProvider(failingProvider{name: "p"}) },
+		"Declare":                func() { c.Declare("host", Meta{Default: "example.com"}) },
+		"LockKey":                func() { c.LockKey("port") },
+		"PinOnRead":              func() { c.PinOnRead("port") },
+		"SetCoercionPolicy":      func() { c.SetCoercionPolicy(CoercionStrict) },
+		"SetHumanReadable":       func() { c.SetHumanReadableNumbers(true) },
+		"SetExtendedDurations":   func() { c.SetExtendedDurations(true) },
+		"SetConfigWeight":        func() { c.SetConfigWeight(100) },
+	}
+	for name, write := range writers {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrFrozen {
+					t.Fatalf("expected %s to panic with ErrFrozen, got %v", name, r)
+				}
+			}()
+			write()
+		}()
+	}
+	if err := c.SetPrecedence(SourceConfig); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen from SetPrecedence, got %v", err)
+	}
+	if err := c.SetBoolWords([]string{"1"}, []string{"0"}); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen from SetBoolWords, got %v", err)
+	}
+	if err := c.set("port", 3); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen from set, got %v", err)
+	}
+
+	if got := c.GetInt("port"); got != 8080 {
+		t.Fatalf("expected port 8080, got %d", got)
+	}
+	if got := c.GetString("host"); got != "localhost" {
+		t.Fatalf("expected host localhost, got %q", got)
+	}
+}
+
+func TestFreezeCancelsStagedRollback(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"port": 8080})
+	if err := c.ApplyStaged(map[string]any{"port": 9090}, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	c.Freeze()
+	time.Sleep(50 * time.Millisecond)
+	if got := c.GetInt("port"); got != 9090 {
+		t.Fatalf("expected the staged change to be kept once frozen, got %d", got)
+	}
+	if err := c.Abort(); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("expected ErrFrozen from Abort, got %v", err)
+	}
+}
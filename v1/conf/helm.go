@@ -0,0 +1,260 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ParseHelmSet parses a Helm --set expression, such as
+// "image.tag=1.2,ports[0].name=http,annotations.example\.com/team=core", into
+// a nested map following Helm's rules: dots separate keys unless escaped with
+// a backslash, [n] indexes lists, which grow as needed, {a,b} is a list,
+// commas separate assignments and values are typed, so "true" is a bool,
+// "8080" an int64 and "null" removes the key.
+func ParseHelmSet(expr string) (map[string]any, error) {
+	return parseHelm(expr, true)
+}
+
+// ParseHelmSetString is ParseHelmSet for --set-string: values are kept as
+// strings.
+func ParseHelmSetString(expr string) (map[string]any, error) {
+	return parseHelm(expr, false)
+}
+
+// ReadHelmValues replaces the config values with Helm-style values: the YAML
+// files are merged in order, maps deeply and null removing a key, then every
+// --set expression is applied on top, as "helm install -f a.yaml -f b.yaml
+// --set x=y" does. Like ReadInConfig, the result goes through the validators
+// and emits change events with ChangeSourceFile.
+func (c *Config) ReadHelmValues(files []string, sets ...string) error {
+	values := make(map[string]any)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		parsed, err := YAMLLoader{}.Load(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		mergeHelm(values, normalizeLoadedMap(parsed))
+	}
+	for _, expr := range sets {
+		if err := applyHelm(values, expr, true); err != nil {
+			return err
+		}
+	}
+	return c.update(ChangeSourceFile, func() error {
+		return c.setValuesLocked(values)
+	})
+}
+
+func parseHelm(expr string, typed bool) (map[string]any, error) {
+	values := make(map[string]any)
+	if err := applyHelm(values, expr, typed); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// applyHelm applies the assignments of expr to values.
+func applyHelm(values map[string]any, expr string, typed bool) error {
+	p := &helmParser{s: []rune(expr), typed: typed}
+	for !p.eof() {
+		if err := p.assign(values); err != nil {
+			return fmt.Errorf("conf: parsing %q: %w", expr, err)
+		}
+		if !p.eof() && p.next() != ',' {
+			return fmt.Errorf("conf: parsing %q: expected ',' at %d", expr, p.pos)
+		}
+	}
+	return nil
+}
+
+// mergeHelm merges src into dst the way Helm coalesces values files.
+func mergeHelm(dst, src map[string]any) {
+	for k, v := range src {
+		if v == nil {
+			delete(dst, k)
+			continue
+		}
+		if sub, ok := v.(map[string]any); ok {
+			if existing, ok := dst[k].(map[string]any); ok {
+				mergeHelm(existing, sub)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+type helmParser struct {
+	s     []rune
+	pos   int
+	typed bool
+}
+
+func (p *helmParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *helmParser) next() rune {
+	r := p.s[p.pos]
+	p.pos++
+	return r
+}
+
+// read returns the text up to the first unescaped rune of stop, unescaping
+// backslashes.
+func (p *helmParser) read(stop string) string {
+	var b strings.Builder
+	for !p.eof() {
+		r := p.s[p.pos]
+		if r == '\\' && p.pos+1 < len(p.s) {
+			b.WriteRune(p.s[p.pos+1])
+			p.pos += 2
+			continue
+		}
+		if strings.ContainsRune(stop, r) {
+			break
+		}
+		b.WriteRune(r)
+		p.pos++
+	}
+	return b.String()
+}
+
+// assign parses one "path=value" assignment into dst.
+func (p *helmParser) assign(dst map[string]any) error {
+	key := p.read(".=[,")
+	if key == "" {
+		return fmt.Errorf("empty key at %d", p.pos)
+	}
+	if p.eof() {
+		return fmt.Errorf("key %q has no value", key)
+	}
+	switch p.next() {
+	case '=':
+		v, set, err := p.value()
+		if err != nil {
+			return err
+		}
+		if !set {
+			delete(dst, key)
+			return nil
+		}
+		dst[key] = v
+	case '.':
+		sub, ok := dst[key].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			dst[key] = sub
+		}
+		return p.assign(sub)
+	case '[':
+		list, _ := dst[key].([]any)
+		list, err := p.assignIndex(list)
+		if err != nil {
+			return err
+		}
+		dst[key] = list
+	default:
+		return fmt.Errorf("key %q has no value", key)
+	}
+	return nil
+}
+
+// maxHelmIndex bounds list indexes, like Helm does, so that a mistyped
+// "a[100000000]=x" cannot allocate a huge list.
+const maxHelmIndex = 65536
+
+// assignIndex parses "n]" followed by the rest of an assignment into list.
+func (p *helmParser) assignIndex(list []any) ([]any, error) {
+	text := p.read("]")
+	if p.eof() {
+		return nil, fmt.Errorf("unterminated index %q", text)
+	}
+	p.next()
+	idx, err := strconv.Atoi(text)
+	if err != nil || idx < 0 {
+		return nil, fmt.Errorf("invalid index %q", text)
+	}
+	if idx > maxHelmIndex {
+		return nil, fmt.Errorf("index %d exceeds the maximum of %d", idx, maxHelmIndex)
+	}
+	for len(list) <= idx {
+		list = append(list, nil)
+	}
+	if p.eof() {
+		return nil, fmt.Errorf("index %d has no value", idx)
+	}
+	switch p.next() {
+	case '=':
+		v, _, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list[idx] = v
+	case '.':
+		sub, ok := list[idx].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			list[idx] = sub
+		}
+		return list, p.assign(sub)
+	case '[':
+		nested, _ := list[idx].([]any)
+		nested, err := p.assignIndex(nested)
+		if err != nil {
+			return nil, err
+		}
+		list[idx] = nested
+	default:
+		return nil, fmt.Errorf("index %d has no value", idx)
+	}
+	return list, nil
+}
+
+// value parses the value of an assignment, a scalar or a {a,b} list. set is
+// false for a typed null.
+func (p *helmParser) value() (v any, set bool, err error) {
+	if !p.eof() && p.s[p.pos] == '{' {
+		p.next()
+		list := []any{}
+		for {
+			list = append(list, p.scalar(p.read(",}")))
+			if p.eof() {
+				return nil, false, fmt.Errorf("unterminated list")
+			}
+			if p.next() == '}' {
+				return list, true, nil
+			}
+		}
+	}
+	v = p.scalar(p.read(","))
+	return v, v != nil || !p.typed, nil
+}
+
+// scalar types text as Helm does for --set, or keeps it for --set-string.
+func (p *helmParser) scalar(text string) any {
+	if !p.typed {
+		return text
+	}
+	switch text {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if len(text) > 1 && text[0] == '0' {
+		return text
+	}
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n
+	}
+	return text
+}
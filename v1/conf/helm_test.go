@@ -0,0 +1,57 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseHelmSet(t *testing.T) {
+	got, err := ParseHelmSet(`image.tag=1.2,replicas=3,debug=true,ports[1].name=http,hosts={a,b},annotations.example\.com/team=core,msg=a\,b`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"image":       map[string]any{"tag": "1.2"},
+		"replicas":    int64(3),
+		"debug":       true,
+		"ports":       []any{nil, map[string]any{"name": "http"}},
+		"hosts":       []any{"a", "b"},
+		"annotations": map[string]any{"example.com/team": "core"},
+		"msg":         "a,b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+	if got, _ := ParseHelmSetString("replicas=3"); got["replicas"] != "3" {
+		t.Fatalf("expected --set-string to keep strings, got %#v", got)
+	}
+	for _, bad := range []string{"a", "=1", "a[x]=1", "a={b", "a[65537]=1", "a[0][100000000]=1"} {
+		if _, err := ParseHelmSet(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestReadHelmValues(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "values.yaml")
+	prod := filepath.Join(dir, "prod.yaml")
+	if err := os.WriteFile(base, []byte("image:\n  repo: app\n  tag: latest\nresources:\n  cpu: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prod, []byte("image:\n  tag: \"1.0\"\nresources: null\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	if err := c.ReadHelmValues([]string{base, prod}, "image.tag=1.1", "replicas=2"); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetString("image.repo") != "app" || c.GetString("image.tag") != "1.1" || c.GetInt("replicas") != 2 {
+		t.Fatalf("unexpected values %v", c.AllSettings())
+	}
+	if c.IsSet("resources") {
+		t.Fatalf("expected null to remove the key")
+	}
+}
@@ -0,0 +1,61 @@
+package conf
+
+// defaultHistorySize is the number of change events kept by a new Config.
+const defaultHistorySize = 100
+
+// changeHistory is a fixed size ring buffer of change events.
+type changeHistory struct {
+	events []ChangeEvent
+	next   int
+	full   bool
+}
+
+func newChangeHistory(size int) *changeHistory {
+	return &changeHistory{events: make([]ChangeEvent, size)}
+}
+
+func (h *changeHistory) add(ev ChangeEvent) {
+	if h == nil || len(h.events) == 0 {
+		return
+	}
+	h.events[h.next] = ev
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// list returns the recorded events, oldest first.
+func (h *changeHistory) list() []ChangeEvent {
+	if h == nil {
+		return nil
+	}
+	if !h.full {
+		return append([]ChangeEvent(nil), h.events[:h.next]...)
+	}
+	out := make([]ChangeEvent, 0, len(h.events))
+	out = append(out, h.events[h.next:]...)
+	return append(out, h.events[:h.next]...)
+}
+
+// SetHistorySize sets how many change events History keeps, 100 by default.
+// Zero disables the history. Resizing keeps the most recent events.
+func (c *Config) SetHistorySize(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := newChangeHistory(n)
+	for _, ev := range c.history.list() {
+		h.add(ev)
+	}
+	c.history = h
+}
+
+// History returns the most recent change events, oldest first.
+func (c *Config) History() []ChangeEvent {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.history.list()
+}
@@ -0,0 +1,36 @@
+package conf
+
+import "testing"
+
+func TestChangeHistoryRing(t *testing.T) {
+	h := newChangeHistory(3)
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		h.add(ChangeEvent{Key: key})
+	}
+	got := h.list()
+	if len(got) != 3 || got[0].Key != "c" || got[1].Key != "d" || got[2].Key != "e" {
+		t.Fatalf("expected last three events oldest first, got %+v", got)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	c := New()
+	c.SetHistorySize(2)
+	c.dispatchChanges([]ChangeEvent{{Key: "a"}, {Key: "b"}, {Key: "c"}})
+
+	got := c.History()
+	if len(got) != 2 || got[0].Key != "b" || got[1].Key != "c" {
+		t.Fatalf("unexpected history %+v", got)
+	}
+
+	c.SetHistorySize(1)
+	if got := c.History(); len(got) != 1 || got[0].Key != "c" {
+		t.Fatalf("expected resize to keep the most recent event, got %+v", got)
+	}
+
+	c.SetHistorySize(0)
+	c.dispatchChanges([]ChangeEvent{{Key: "d"}})
+	if got := c.History(); len(got) != 0 {
+		t.Fatalf("expected disabled history, got %+v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package conf
+
+import "time"
+
+// HookSourceFile is the source passed to Hooks for loads of the config file.
+// Provider loads use the provider name.
+const HookSourceFile = "file"
+
+// Hooks receives the lifecycle events of a Config, so that instrumentation
+// packages can observe loads, changes and watchers through one value. Embed
+// NopHooks to implement only some of the methods. Hooks are called without
+// the config lock held and must not block.
+type Hooks interface {
+	// OnLoadStart is called before the config file or a provider is read.
+	OnLoadStart(source string)
+	// OnLoadSuccess is called after source was read and applied.
+	OnLoadSuccess(source string, elapsed time.Duration)
+	// OnLoadError is called when reading or applying source failed.
+	OnLoadError(source string, err error)
+	// OnChangeApplied receives the change events of every applied change.
+	OnChangeApplied(events []ChangeEvent)
+	// OnWatchStart is called when watching the config file, by path, or a
+	// provider, by name, starts.
+	OnWatchStart(target string)
+	// OnWatchStop is called when that watch ends.
+	OnWatchStop(target string)
+}
+
+// NopHooks implements Hooks with methods doing nothing.
+type NopHooks struct{}
+
+func (NopHooks) OnLoadStart(string)                  {}
+func (NopHooks) OnLoadSuccess(string, time.Duration) {}
+func (NopHooks) OnLoadError(string, error)           {}
+func (NopHooks) OnChangeApplied([]ChangeEvent)       {}
+func (NopHooks) OnWatchStart(string)                 {}
+func (NopHooks) OnWatchStop(string)                  {}
+
+// AddHooks registers h to receive the lifecycle events of c.
+func (c *Config) AddHooks(h Hooks) {
+	if h == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, h)
+}
+
+// runHooks calls fn for every registered Hooks. It must be called without
+// holding c.mu.
+func (c *Config) runHooks(fn func(Hooks)) {
+	c.mu.RLock()
+	hooks := append([]Hooks(nil), c.hooks...)
+	c.mu.RUnlock()
+	for _, h := range hooks {
+		fn(h)
+	}
+}
+
+// hookLoad reports the start of loading source and returns the function
+// reporting its outcome.
+func (c *Config) hookLoad(source string) func(error) {
+	c.runHooks(func(h Hooks) { h.OnLoadStart(source) })
+	start := time.Now()
+	return func(err error) {
+		if err != nil {
+			c.runHooks(func(h Hooks) { h.OnLoadError(source, err) })
+			return
+		}
+		elapsed := time.Since(start)
+		c.runHooks(func(h Hooks) { h.OnLoadSuccess(source, elapsed) })
+	}
+}
@@ -0,0 +1,73 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingHooks struct {
+	NopHooks
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *recordingHooks) add(format string, args ...any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, fmt.Sprintf(format, args...))
+}
+
+func (h *recordingHooks) OnLoadStart(source string) { h.add("start %s", source) }
+
+func (h *recordingHooks) OnLoadSuccess(source string, _ time.Duration) {
+	h.add("success %s", source)
+}
+
+func (h *recordingHooks) OnLoadError(source string, err error) { h.add("error %s", source) }
+
+func (h *recordingHooks) OnChangeApplied(events []ChangeEvent) {
+	h.add("changes %d %s", len(events), events[0].Source)
+}
+
+func (h *recordingHooks) OnWatchStart(target string) { h.add("watch") }
+
+func (h *recordingHooks) OnWatchStop(target string) { h.add("unwatch") }
+
+func (h *recordingHooks) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return strings.Join(h.events, ", ")
+}
+
+func TestHooks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h := &recordingHooks{}
+	c := New()
+	c.AddHooks(h)
+	c.SetConfigFile(path)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	c.MergeConfigMap(map[string]any{"port": 2, "host": "a"})
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path)
+	c.ReadInConfig()
+
+	want := "start file, success file, changes 2 runtime, watch, unwatch, start file, error file"
+	if got := h.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
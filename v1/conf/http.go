@@ -0,0 +1,188 @@
+package conf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrHashMismatch is returned by providers whose payload does not match any
+// of the SHA-256 digests it is pinned to.
+var ErrHashMismatch = errors.New("conf: config payload does not match pinned hash")
+
+// Decoder decodes raw configuration data. Format is either a file extension
+// or a MIME type.
+type Decoder interface {
+	Decode(data []byte, format string) (map[string]any, error)
+}
+
+func defaultMIMETypes() map[string]string {
+	return map[string]string{
+		"application/json":   "json",
+		"text/json":          "json",
+		"application/yaml":   "yaml",
+		"application/x-yaml": "yaml",
+		"text/yaml":          "yaml",
+		"text/x-yaml":        "yaml",
+		"application/toml":   "toml",
+		"text/toml":          "toml",
+		"application/xml":    "xml",
+		"text/xml":           "xml",
+	}
+}
+
+// RegisterMIMEType maps a MIME type onto the loader registered for ext, e.g.
+// RegisterMIMEType("application/vnd.acme+json", "json").
+func (c *Config) RegisterMIMEType(mimeType, ext string) {
+	mimeType, ext = strings.ToLower(strings.TrimSpace(mimeType)), normalizeExt(ext)
+	if mimeType == "" || ext == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mimeTypes == nil {
+		c.mimeTypes = make(map[string]string)
+	}
+	c.mimeTypes[mimeType] = ext
+}
+
+// Decode decodes data with the loader registered for format, which is either
+// a file extension or a MIME type, parameters such as charset included. It
+// returns ErrUnsupportedFormat when no loader matches.
+func (c *Config) Decode(data []byte, format string) (map[string]any, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if strings.Contains(format, "/") {
+		mediaType, _, err := mime.ParseMediaType(format)
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		ext, ok := c.mimeTypes[mediaType]
+		if !ok {
+			return nil, ErrUnsupportedFormat
+		}
+		format = ext
+	}
+	return c.decodeConfig(data, format)
+}
+
+// HTTPProvider is a Provider fetching the configuration from a URL. The
+// loader is chosen from the Content-Type of the response, falling back to
+// the extension of the URL path when the type is missing or unknown.
+type HTTPProvider struct {
+	URL     string
+	Header  http.Header
+	Client  *http.Client
+	Decoder Decoder
+	// Verifier, when set, checks the payload against the detached
+	// signature served at SignatureURL, by default URL with ".minisig"
+	// appended for a minisign verifier and ".sig" for others, and rejects
+	// it when it does not match.
+	Verifier     Verifier
+	SignatureURL string
+	// SHA256, when not empty, pins the payload to the listed hex encoded
+	// SHA-256 digests; any other payload is rejected with ErrHashMismatch.
+	SHA256 []string
+}
+
+// NewHTTPProvider returns a provider fetching rawURL and decoding the
+// response with dec, usually the Config it is added to.
+func NewHTTPProvider(rawURL string, dec Decoder) *HTTPProvider {
+	return &HTTPProvider{URL: rawURL, Decoder: dec}
+}
+
+// Name returns the URL of the provider.
+func (p *HTTPProvider) Name() string {
+	return p.URL
+}
+
+// Load fetches and decodes the configuration.
+func (p *HTTPProvider) Load(ctx context.Context) (map[string]any, error) {
+	if p.Decoder == nil {
+		return nil, errors.New("no decoder set")
+	}
+	data, header, err := p.fetch(ctx, p.URL)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSHA256(data, p.SHA256); err != nil {
+		return nil, fmt.Errorf("%s: %w", p.URL, err)
+	}
+	if p.Verifier != nil {
+		sigURL := p.SignatureURL
+		if sigURL == "" {
+			sigURL = p.URL + signatureSuffix(p.Verifier)
+		}
+		sig, _, err := p.fetch(ctx, sigURL)
+		if err != nil {
+			return nil, fmt.Errorf("fetching signature: %w", err)
+		}
+		if err := p.Verifier.Verify(data, sig); err != nil {
+			return nil, err
+		}
+	}
+
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		values, err := p.Decoder.Decode(data, contentType)
+		if !errors.Is(err, ErrUnsupportedFormat) {
+			return values, err
+		}
+	}
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	return p.Decoder.Decode(data, path.Ext(u.Path))
+}
+
+// fetch returns the body and headers of a successful GET of rawURL.
+func (p *HTTPProvider) fetch(ctx context.Context, rawURL string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, values := range p.Header {
+		req.Header[key] = values
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, resp.Header, nil
+}
+
+// checkSHA256 reports ErrHashMismatch when allowed is not empty and the
+// digest of data is not one of its hex encoded entries.
+func checkSHA256(data []byte, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	for _, h := range allowed {
+		if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(h), "sha256:"), digest) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: got sha256:%s", ErrHashMismatch, digest)
+}
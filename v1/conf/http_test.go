@@ -0,0 +1,94 @@
+package conf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPProviderContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app":
+			w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+			w.Write([]byte("server:\n  port: 9000\n"))
+		case "/app.toml":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("name = \"demo\"\n"))
+		case "/acme":
+			w.Header().Set("Content-Type", "application/vnd.acme+json")
+			w.Write([]byte(`{"region": "eu"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New()
+	c.SetDefault("server.port", 8080)
+	c.SetDefault("name", "default")
+	c.RegisterMIMEType("application/vnd.acme+json", "json")
+	c.AddProvider(NewHTTPProvider(srv.URL+"/app", c))
+	c.AddProvider(NewHTTPProvider(srv.URL+"/app.toml", c))
+	c.AddProvider(NewHTTPProvider(srv.URL+"/acme", c))
+
+	var events []ChangeEvent
+	c.Subscribe(nil, func(e ChangeEvent) { events = append(events, e) })
+	if err := c.ReadProviders(context.Background()); err != nil {
+		t.Fatalf("failed to read providers: %v", err)
+	}
+	if got := c.GetInt("server.port"); got != 9000 {
+		t.Fatalf("expected port from yaml content type, got %d", got)
+	}
+	if got := c.GetString("name"); got != "demo" {
+		t.Fatalf("expected name from the extension fallback, got %q", got)
+	}
+	if got := c.GetString("region"); got != "eu" {
+		t.Fatalf("expected region from registered MIME type, got %q", got)
+	}
+	if len(events) != 3 || events[0].Source != ChangeSourceProvider {
+		t.Fatalf("expected 3 provider change events, got %+v", events)
+	}
+
+	c.MergeConfigMap(map[string]any{"server": map[string]any{"port": 7000}})
+	if got := c.GetInt("server.port"); got != 7000 {
+		t.Fatalf("expected config values to override providers, got %d", got)
+	}
+
+	c.AddProvider(NewHTTPProvider(srv.URL+"/missing", c))
+	if err := c.ReadProviders(context.Background()); err == nil {
+		t.Fatalf("expected error for failing provider")
+	}
+	if got := c.GetString("region"); got != "eu" {
+		t.Fatalf("expected provider layer to be kept on failure, got %q", got)
+	}
+}
+
+func TestHTTPProviderPinnedHash(t *testing.T) {
+	payload := `{"replicas": 3}`
+	served := payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(served))
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256([]byte(payload))
+
+	c := New()
+	p := NewHTTPProvider(srv.URL+"/app.json", c)
+	p.SHA256 = []string{"sha256:" + hex.EncodeToString(sum[:])}
+	c.AddProvider(p)
+	if err := c.ReadProviders(context.Background()); err != nil || c.GetInt("replicas") != 3 {
+		t.Fatalf("expected pinned payload to load, got %v", err)
+	}
+	served = `{"replicas": 0}`
+	if err := c.ReadProviders(context.Background()); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("expected ErrHashMismatch, got %v", err)
+	}
+	if c.GetInt("replicas") != 3 {
+		t.Fatalf("expected rejected payload not to be applied")
+	}
+}
@@ -0,0 +1,47 @@
+package conf
+
+// Resolver resolves the value of a key.
+type Resolver func(key string) (any, bool)
+
+// Interceptor wraps the resolution done by the getters. It can observe the
+// lookup, answer it itself or call next to continue down the chain, which
+// ends with the regular layered resolution.
+type Interceptor func(key string, next Resolver) (any, bool)
+
+// Use appends interceptors to the chain wrapping every getter; the first one
+// registered is the outermost. Typical uses are tracing lookups, injecting
+// values in tests and custom fallbacks:
+//
+//	cfg.Use(func(key string, next conf.Resolver) (any, bool) {
+//		v, ok := next(key)
+//		if !ok {
+//			return legacy.Lookup(key)
+//		}
+//		return v, true
+//	})
+//
+// Snapshots, validators and change detection see the layered values only.
+func (c *Config) Use(interceptors ...Interceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	for _, ic := range interceptors {
+		if ic != nil {
+			c.interceptors = append(c.interceptors, ic)
+		}
+	}
+	c.publishLocked()
+}
+
+// intercepted resolves key through the interceptor chain.
+func (s *state) intercepted(key string) (any, bool) {
+	if len(s.intercept) == 0 {
+		return s.get(key)
+	}
+	next := Resolver(s.get)
+	for i := len(s.intercept) - 1; i >= 0; i-- {
+		ic, inner := s.intercept[i], next
+		next = func(key string) (any, bool) { return ic(key, inner) }
+	}
+	return next(key)
+}
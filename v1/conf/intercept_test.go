@@ -0,0 +1,35 @@
+package conf
+
+import "testing"
+
+func TestInterceptors(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"host": "prod"}})
+
+	var trace []string
+	c.Use(func(key string, next Resolver) (any, bool) {
+		trace = append(trace, key)
+		return next(key)
+	}, func(key string, next Resolver) (any, bool) {
+		if key == "db.host" {
+			return "localhost", true
+		}
+		if v, ok := next(key); ok {
+			return v, true
+		}
+		return "fallback", true
+	})
+
+	if got := c.GetString("db.host"); got != "localhost" {
+		t.Fatalf("expected injected value, got %q", got)
+	}
+	if got := c.GetString("missing"); got != "fallback" {
+		t.Fatalf("expected custom fallback, got %q", got)
+	}
+	if len(trace) != 2 || trace[0] != "db.host" {
+		t.Fatalf("expected outer interceptor to trace both lookups, got %v", trace)
+	}
+	if v, _ := c.Snapshot().Get("db.host"); v != "prod" {
+		t.Fatalf("expected snapshots to bypass interceptors, got %v", v)
+	}
+}
@@ -0,0 +1,96 @@
+package conf
+
+import (
+	"bytes"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// K8sOption customizes the manifests rendered by ExportK8sConfigMap.
+type K8sOption func(*k8sOptions)
+
+type k8sOptions struct {
+	subtree  string
+	envNames bool
+}
+
+// K8sSubtree exports only the keys under key, e.g. K8sSubtree("database").
+func K8sSubtree(key string) K8sOption {
+	return func(o *k8sOptions) {
+		o.subtree = key
+	}
+}
+
+// K8sEnvNames keys the data by the environment variable overriding each key,
+// such as APP_SERVER_PORT, so the manifests can be consumed with envFrom.
+func K8sEnvNames() K8sOption {
+	return func(o *k8sOptions) {
+		o.envNames = true
+	}
+}
+
+type k8sManifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   k8sMetadata       `yaml:"metadata"`
+	Type       string            `yaml:"type,omitempty"`
+	Data       map[string]string `yaml:"data,omitempty"`
+	StringData map[string]string `yaml:"stringData,omitempty"`
+}
+
+type k8sMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// ExportK8sConfigMap renders the effective configuration as a Kubernetes
+// ConfigMap named name in namespace, one data entry per leaf key, its levels
+// joined with dots whatever the key delimiter. Secret keys,
+// see MarkSecret, and lists holding secrets in their entries are routed to a Secret of the same name, rendered as a
+// second YAML document when there are any. Values are formatted as by
+// AllSettingsFlatString.
+func (c *Config) ExportK8sConfigMap(name, namespace string, opts ...K8sOption) ([]byte, error) {
+	var o k8sOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := c.load()
+	meta := k8sMetadata{Name: name, Namespace: namespace}
+	configMap := k8sManifest{APIVersion: "v1", Kind: "ConfigMap", Metadata: meta, Data: map[string]string{}}
+	secret := k8sManifest{APIVersion: "v1", Kind: "Secret", Metadata: meta, Type: "Opaque", StringData: map[string]string{}}
+
+	secrets := c.secretSet()
+	for key, v := range s.effective() {
+		if o.subtree != "" && key != o.subtree && !strings.HasPrefix(key, o.subtree+s.delim) {
+			continue
+		}
+		// ConfigMap keys only allow letters, digits, "-", "_" and ".".
+		entry := strings.ReplaceAll(key, s.delim, ".")
+		if o.envNames {
+			entry = s.envName(key)
+		}
+		value := flatString(v)
+		if secrets.contains(key, v) {
+			secret.StringData[entry] = value
+		} else {
+			configMap.Data[entry] = value
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(configMap); err != nil {
+		return nil, err
+	}
+	if len(secret.StringData) > 0 {
+		if err := enc.Encode(secret); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
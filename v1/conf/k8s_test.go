@@ -0,0 +1,65 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportK8sConfigMap(t *testing.T) {
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.MergeConfigMap(map[string]any{
+		"server":   map[string]any{"port": 8080, "hosts": []any{"a", "b"}},
+		"database": map[string]any{"host": "db", "password": "hunter2"},
+	})
+
+	out, err := c.ExportK8sConfigMap("app", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: prod
+data:
+  database.host: db
+  server.hosts: a,b
+  server.port: "8080"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+  namespace: prod
+type: Opaque
+stringData:
+  database.password: hunter2
+`
+	if string(out) != want {
+		t.Fatalf("unexpected manifests:\n%s", out)
+	}
+
+	out, err = c.ExportK8sConfigMap("app", "", K8sSubtree("server"), K8sEnvNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "APP_SERVER_PORT: \"8080\"") || strings.Contains(string(out), "Secret") || strings.Contains(string(out), "namespace") {
+		t.Fatalf("unexpected subtree manifest:\n%s", out)
+	}
+}
+
+func TestExportK8sConfigMapSecretsInLists(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"users": []any{
+		map[string]any{"name": "a", "password": "hunter2"},
+	}}})
+	out, err := c.ExportK8sConfigMap("app", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMap, secret, _ := strings.Cut(string(out), "---")
+	if strings.Contains(configMap, "hunter2") || !strings.Contains(secret, "hunter2") {
+		t.Fatalf("expected a list holding secrets to be routed to the Secret:\n%s", out)
+	}
+}
@@ -0,0 +1,53 @@
+package conf
+
+// SetDefaultFunc sets a default for key computed by fn every time the key is
+// resolved from the defaults layer, so that it can depend on other settings:
+//
+//	cfg.SetDefaultFunc("cache.size", func(c *conf.Config) any {
+//		if c.GetString("profile") == "small" {
+//			return 64
+//		}
+//		return 1024
+//	})
+//
+// fn receives a read-only view of the configuration being resolved, so a
+// snapshot, a change event or a drift report sees the default computed from
+// the same settings it holds. fn must not read key itself nor modify the
+// view. A default set with SetDefault for the same key takes precedence.
+func (c *Config) SetDefaultFunc(key string, fn func(c *Config) any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	if fn == nil {
+		delete(c.defaultFuncs, key)
+	} else {
+		if c.defaultFuncs == nil {
+			c.defaultFuncs = make(map[string]func(*Config) any)
+		}
+		c.defaultFuncs[key] = fn
+	}
+	c.publishLocked()
+}
+
+// getDefault returns the default for key, computing it when it was set with
+// SetDefaultFunc.
+func (s *state) getDefault(key string) (any, bool) {
+	if v, ok := fetchValue(s.defaults, key, s.delim); ok {
+		return v, true
+	}
+	if fn, ok := s.computed[key]; ok && s.view != nil {
+		return fn(s.view), true
+	}
+	return nil, false
+}
+
+// bind gives s the read-only Config its computed defaults are evaluated
+// against, and returns s.
+func (s *state) bind() *state {
+	s.view = nil
+	if len(s.computed) > 0 {
+		s.view = &Config{logger: s.logger}
+		s.view.current.Store(s)
+	}
+	return s
+}
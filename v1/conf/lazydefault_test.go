@@ -0,0 +1,71 @@
+package conf
+
+import "testing"
+
+func TestSetDefaultFunc(t *testing.T) {
+	c := New()
+	c.SetDefault("profile", "large")
+	c.SetDefaultFunc("cache.size", func(c *Config) any {
+		if c.GetString("profile") == "small" {
+			return 64
+		}
+		return 1024
+	})
+
+	if got := c.GetInt("cache.size"); got != 1024 {
+		t.Fatalf("expected 1024 for the large profile, got %d", got)
+	}
+	c.MergeConfigMap(map[string]any{"profile": "small"})
+	if got := c.GetInt("cache.size"); got != 64 {
+		t.Fatalf("expected default to follow profile, got %d", got)
+	}
+	if _, ok := c.AllSettingsFlat()["cache.size"]; !ok {
+		t.Fatalf("expected computed default among the settings")
+	}
+	c.MergeConfigMap(map[string]any{"cache": map[string]any{"size": 8}})
+	if got := c.GetInt("cache.size"); got != 8 {
+		t.Fatalf("expected config value to win over the computed default, got %d", got)
+	}
+}
+
+func TestSetDefaultFuncUsesResolvedState(t *testing.T) {
+	c := New()
+	c.SetDefault("profile", "large")
+	c.SetDefaultFunc("cache.size", func(c *Config) any {
+		if c.GetString("profile") == "small" {
+			return 64
+		}
+		return 1024
+	})
+	before := c.Snapshot()
+
+	var events []ChangeEvent
+	c.Subscribe(nil, func(e ChangeEvent) { events = append(events, e) })
+	c.Set("profile", "small")
+
+	if got := before.GetInt("cache.size"); got != 1024 {
+		t.Fatalf("expected the snapshot to keep its computed default, got %d", got)
+	}
+	found := false
+	for _, e := range events {
+		if e.Key == "cache.size" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a change event for the computed default, got %v", events)
+	}
+	drift := c.DriftFrom(before)
+	found = false
+	for _, e := range drift {
+		if e.Key == "cache.size" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the computed default to drift, got %v", drift)
+	}
+	if got := before.With(map[string]any{"profile": "small"}).GetInt("cache.size"); got != 64 {
+		t.Fatalf("expected the derived snapshot to recompute the default, got %d", got)
+	}
+}
@@ -1,14 +1,22 @@
 package conf
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"io"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	ini "gopkg.in/ini.v1"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrUnsupportedFormat is returned when no loader or encoder is registered
+// for a format.
+var ErrUnsupportedFormat = errors.New("unsupported config file type")
+
 // Loader defines the behavior for parsing configuration data into a map.
 type Loader interface {
 	Load(data []byte) (map[string]any, error)
@@ -17,24 +25,41 @@ type Loader interface {
 // JSONLoader implements Loader for JSON documents.
 type JSONLoader struct{}
 
-// Load decodes JSON data into a map representation.
+// Load decodes JSON data into a map representation. Numbers are float64
+// unless that would round them, in which case they are kept as json.Number.
 func (JSONLoader) Load(data []byte) (map[string]any, error) {
 	values := make(map[string]any)
-	if err := json.Unmarshal(data, &values); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
 		return nil, err
 	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	preciseNumbers(values)
 	return values, nil
 }
 
 // YAMLLoader implements Loader for YAML documents.
 type YAMLLoader struct{}
 
-// Load decodes YAML data into a map representation.
+// Load decodes YAML data into a map representation. Numbers that float64
+// would round are kept as json.Number, like JSONLoader does.
 func (YAMLLoader) Load(data []byte) (map[string]any, error) {
 	values := make(map[string]any)
-	if err := yaml.Unmarshal(data, &values); err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		return values, nil
+	}
+	preciseYAMLNumbers(&doc)
+	if err := doc.Decode(&values); err != nil {
 		return nil, err
 	}
+	yamlNumbers(values)
 	return values, nil
 }
 
@@ -79,7 +104,7 @@ func (XMLLoader) Load(data []byte) (map[string]any, error) {
 }
 
 func defaultLoaders() map[string]Loader {
-	return map[string]Loader{
+	loaders := map[string]Loader{
 		"json": JSONLoader{},
 		"yaml": YAMLLoader{},
 		"yml":  YAMLLoader{},
@@ -87,4 +112,65 @@ func defaultLoaders() map[string]Loader {
 		"ini":  INILoader{},
 		"xml":  XMLLoader{},
 	}
+	globalLoaders.Range(func(ext, loader any) bool {
+		loaders[ext.(string)] = loader.(Loader)
+		return true
+	})
+	return loaders
+}
+
+// globalLoaders holds the loaders registered with RegisterGlobalLoader.
+var globalLoaders sync.Map
+
+// RegisterGlobalLoader registers loader for ext in every Config, typically
+// from the init function of a package shipping a proprietary format, so any
+// binary importing it can read such files. Configs created afterwards start
+// with the loader, replacing a built-in one for the same extension, and
+// existing Configs use it for extensions they have no loader of their own
+// for. Config.RegisterLoader takes precedence over global loaders.
+func RegisterGlobalLoader(ext string, loader Loader) {
+	normalized := normalizeExt(ext)
+	if normalized == "" || loader == nil {
+		return
+	}
+	globalLoaders.Store(normalized, loader)
+}
+
+// globalLoader returns the global loader registered for format.
+func globalLoader(format string) (Loader, bool) {
+	v, ok := globalLoaders.Load(format)
+	if !ok {
+		return nil, false
+	}
+	return v.(Loader), true
+}
+
+// AliasExtension makes files with the alias extension use the loader and
+// encoder registered for target, e.g. AliasExtension("conf", "ini"). Both
+// extensions are normalized as in RegisterLoader and the target is resolved
+// when a file is read, so a loader registered later for it is honored.
+func (c *Config) AliasExtension(alias, target string) {
+	alias, target = normalizeExt(alias), normalizeExt(target)
+	if alias == "" || target == "" || alias == target {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.aliases == nil {
+		c.aliases = make(map[string]string)
+	}
+	c.aliases[alias] = target
+}
+
+// formatLocked normalizes ext and resolves it through the registered aliases.
+func (c *Config) formatLocked(ext string) string {
+	format := normalizeExt(ext)
+	for i := 0; i < len(c.aliases); i++ {
+		target, ok := c.aliases[format]
+		if !ok {
+			break
+		}
+		format = target
+	}
+	return format
 }
@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GetLocation returns the time zone named by the value for the key, such as
+// "Europe/Rome", "UTC" or "Local", or nil when the key is missing or the zone
+// is unknown.
+func (c *Config) GetLocation(key string) *time.Location {
+	loc, err := c.GetLocationE(key)
+	c.noteCoercion(err)
+	return loc
+}
+
+// GetLocationE is like GetLocation but reports missing keys and unknown
+// zones. Zones are loaded from the system database, or from the one embedded
+// with the time/tzdata package.
+func (c *Config) GetLocationE(key string) (*time.Location, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	name, ok := v.(string)
+	if !ok {
+		return nil, &ConversionError{Key: key, Value: v, Target: "time zone"}
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ConversionError{Key: key, Value: v, Target: "time zone"}
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", &ConversionError{Key: key, Value: v, Target: "time zone"}, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,37 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetLocation(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"utc":     "UTC",
+		"local":   "Local",
+		"rome":    "Europe/Rome",
+		"unknown": "Mars/Olympus_Mons",
+	})
+
+	if c.GetLocation("utc") != time.UTC || c.GetLocation("local") != time.Local {
+		t.Fatalf("expected UTC and Local")
+	}
+	if _, err := c.GetLocationE("rome"); err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	if got := c.GetLocation("rome").String(); got != "Europe/Rome" {
+		t.Fatalf("expected Europe/Rome, got %s", got)
+	}
+	var convErr *ConversionError
+	if _, err := c.GetLocationE("unknown"); !errors.As(err, &convErr) || convErr.Key != "unknown" {
+		t.Fatalf("expected conversion error for an unknown zone, got %v", err)
+	}
+	if c.GetLocation("unknown") != nil {
+		t.Fatalf("expected nil for an unknown zone")
+	}
+	if _, err := c.GetLocationE("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package conf
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// LockKey protects the keys matching the patterns, and everything below
+// them, from being overridden at runtime: once the config file defines such
+// a key, environment variables, Set and MergeConfigMap cannot change it.
+// Rejected overrides are reported to the logger, once per key and source.
+// Patterns use the same syntax as MarkSecret.
+func (c *Config) LockKey(patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	for _, pattern := range patterns {
+		if pattern != "" && !containsString(c.locked, pattern) {
+			c.locked = append(c.locked, pattern)
+		}
+	}
+	c.publishLocked()
+}
+
+// IsLocked reports whether key is protected by LockKey.
+func (c *Config) IsLocked(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return matchKeyOrParent(c.locked, key, c.delimLocked())
+}
+
+// lockGuard reports rejected overrides of locked keys. It is shared by every
+// published state so each violation is logged only once.
+type lockGuard struct {
+	reported sync.Map
+}
+
+func (g *lockGuard) report(logger Logger, key, via string) {
+	if _, loaded := g.reported.LoadOrStore(key+"\x00"+via, struct{}{}); loaded {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("conf: rejected override of locked key", "key", key, "source", via)
+}
+
+// stripLockedLocked removes from data, a map about to be merged at runtime,
+// the locked keys already defined in the values layer.
+func (c *Config) stripLockedLocked(data map[string]any, via string) map[string]any {
+	if len(c.locked) == 0 {
+		return data
+	}
+	sep := c.delimLocked()
+	flat := make(map[string]any)
+	flattenInto("", data, flat, sep)
+	var stripped map[string]any
+	for key := range flat {
+		if !matchKeyOrParent(c.locked, key, sep) {
+			continue
+		}
+		if _, ok := fetchValue(c.values, key, sep); !ok {
+			continue
+		}
+		if stripped == nil {
+			stripped = cloneMap(data)
+		}
+		deletePath(stripped, key, sep)
+		c.locks.report(c.logger, key, via)
+	}
+	if stripped == nil {
+		return data
+	}
+	return stripped
+}
+
+// deletePath removes key, split at every sep, from the nested map m.
+func deletePath(m map[string]any, key, sep string) {
+	if _, ok := m[key]; ok {
+		delete(m, key)
+		return
+	}
+	parts := strings.Split(key, sep)
+	for i := 1; i < len(parts); i++ {
+		if sub, ok := m[strings.Join(parts[:i], sep)].(map[string]any); ok {
+			deletePath(sub, strings.Join(parts[i:], sep), sep)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLockKey(t *testing.T) {
+	t.Setenv("APP_SECURITY_TLS", "false")
+	t.Setenv("APP_SERVER_PORT", "9000")
+
+	c := New()
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+	c.SetEnvPrefix("APP")
+	c.AutomaticEnv()
+	c.SetConfigType("yaml")
+	if err := c.ReadConfig(strings.NewReader("security:\n  tls: true\nserver:\n  port: 8080\n")); err != nil {
+		t.Fatal(err)
+	}
+	c.LockKey("security.*")
+	if !c.IsLocked("security.tls") || c.IsLocked("server.port") {
+		t.Fatalf("unexpected locked keys")
+	}
+
+	if !c.GetBool("security.tls") {
+		t.Fatalf("expected locked key to ignore env override")
+	}
+	if got := c.GetInt("server.port"); got != 9000 {
+		t.Fatalf("expected unlocked key to honor env override, got %d", got)
+	}
+	c.GetBool("security.tls")
+
+	c.MergeConfigMap(map[string]any{"security": map[string]any{"tls": false, "hsts": true}})
+	if !c.GetBool("security.tls") {
+		t.Fatalf("expected locked key to ignore runtime merge")
+	}
+	if !c.GetBool("security.hsts") {
+		t.Fatalf("expected locked key not defined by the file to be merged")
+	}
+
+	if got := len(logger.Messages()); got != 2 {
+		t.Fatalf("expected one report per key and source, got %v", logger.Messages())
+	}
+}
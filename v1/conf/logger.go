@@ -0,0 +1,49 @@
+package conf
+
+import "log/slog"
+
+// Logger receives the diagnostics Config cannot return to a caller, such as
+// failed reloads triggered by the watcher. Arguments after msg are
+// alternating keys and values. *slog.Logger satisfies Logger.
+type Logger interface {
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+// SetLogger replaces the logger used for diagnostics, slog.Default() unless
+// set. A nil logger discards every message.
+func (c *Config) SetLogger(l Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if l == nil {
+		l = nopLogger{}
+	}
+	c.logger = l
+	c.publishLocked()
+}
+
+func (c *Config) log() Logger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.logLocked()
+}
+
+func (c *Config) logLocked() Logger {
+	if c.logger == nil {
+		return slog.Default()
+	}
+	return c.logger
+}
+
+// Redacted returns the effective configuration as a nested map in which the
+// values of secret keys are masked, including inside lists. It is meant for
+// logging integrations.
+func (c *Config) Redacted() map[string]any {
+	redacted, _ := c.secretSet().redact("", c.load().settings(), redactedValue).(map[string]any)
+	return redacted
+}
@@ -0,0 +1,83 @@
+package conf
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Warn(msg string, args ...any) {
+	l.record(msg)
+}
+
+func (l *recordingLogger) Error(msg string, args ...any) {
+	l.record(msg)
+}
+
+func (l *recordingLogger) record(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+}
+
+func (l *recordingLogger) Messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.messages...)
+}
+
+func TestSetLoggerReceivesReloadErrors(t *testing.T) {
+	tmp, err := os.CreateTemp("", "cfg*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := os.WriteFile(tmp.Name(), []byte("value: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &recordingLogger{}
+	c := New()
+	c.SetLogger(logger)
+	c.SetConfigFile(tmp.Name())
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := os.WriteFile(tmp.Name(), []byte("::invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+
+	messages := logger.Messages()
+	if len(messages) == 0 || messages[0] != "conf: failed to reload config" {
+		t.Fatalf("expected reload failure to be logged, got %#v", messages)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"database": map[string]any{"host": "db", "password": "hunter2"},
+		"db":       map[string]any{"users": []any{map[string]any{"password": "s3cr3t"}}},
+	})
+	redacted := c.Redacted()
+	db := redacted["database"].(map[string]any)
+	if db["password"] != "******" || db["host"] != "db" {
+		t.Fatalf("unexpected redacted settings %#v", db)
+	}
+	if strings.Contains(formatValue(redacted), "s3cr3t") {
+		t.Fatalf("expected secrets inside lists to be redacted, got %#v", redacted)
+	}
+}
@@ -0,0 +1,62 @@
+package conf
+
+import (
+	"errors"
+	"io"
+)
+
+// MergeInConfig reads the config file, found the same way as by
+// ReadInConfig, and merges it on top of the values already loaded instead of
+// replacing them, so a base file can be layered with an override file:
+//
+//	cfg.SetConfigFile("base.yaml")
+//	cfg.ReadInConfig()
+//	cfg.SetConfigFile("override.yaml")
+//	cfg.MergeInConfig()
+//
+// Fallback files are not tried. The merged values go through the validators
+// and emit change events with ChangeSourceFile.
+func (c *Config) MergeInConfig() error {
+	done := c.hookLoad(HookSourceFile)
+	err := c.update(ChangeSourceFile, func() error {
+		if err := c.takeFaultLocked(HookSourceFile); err != nil {
+			return err
+		}
+		file, searched, err := c.searchConfigFileLocked()
+		c.searched = searched
+		if err != nil || file == "" {
+			return err
+		}
+		parsed, err := c.readConfigFileLocked(file)
+		if err != nil {
+			return err
+		}
+		if err := c.mergeConfigMapLocked(parsed); err != nil {
+			return err
+		}
+		c.fileUsed = file
+		return nil
+	})
+	done(err)
+	return err
+}
+
+// MergeConfig decodes r according to the config type and merges it on top
+// of the values already loaded. Unlike ReadConfig, it emits change events
+// with ChangeSourceFile.
+func (c *Config) MergeConfig(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.update(ChangeSourceFile, func() error {
+		if c.cfgType == "" {
+			return errors.New("config type not set")
+		}
+		parsed, err := c.decodeConfig(data, c.cfgType)
+		if err != nil {
+			return err
+		}
+		return c.mergeConfigMapLocked(parsed)
+	})
+}
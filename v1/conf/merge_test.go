@@ -0,0 +1,52 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMergeInConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "override.yaml")
+	if err := os.WriteFile(base, []byte("db:\n  host: base\n  port: 5432\nname: app\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(override, []byte("db:\n  host: prod\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	var events []ChangeEvent
+	c.Subscribe(nil, func(ev ChangeEvent) { events = append(events, ev) })
+	c.SetConfigFile(base)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	c.SetConfigFile(override)
+	if err := c.MergeInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetString("db.host"); got != "prod" {
+		t.Fatalf("expected db.host prod, got %q", got)
+	}
+	if got := c.GetInt("db.port"); got != 5432 || c.GetString("name") != "app" {
+		t.Fatalf("expected base values to be kept")
+	}
+	if c.ConfigFileUsed() != override {
+		t.Fatalf("expected ConfigFileUsed %s, got %s", override, c.ConfigFileUsed())
+	}
+	if len(events) != 1 || events[0].Key != "db.host" || events[0].Source != ChangeSourceFile {
+		t.Fatalf("expected one file change for db.host, got %+v", events)
+	}
+
+	c.SetConfigType("json")
+	if err := c.MergeConfig(strings.NewReader(`{"db": {"port": 6432}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("db.port"); got != 6432 || c.GetString("db.host") != "prod" {
+		t.Fatalf("expected MergeConfig to layer on top, got port %d", got)
+	}
+}
@@ -0,0 +1,155 @@
+package conf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Type describes the expected type of a declared key.
+type Type int
+
+const (
+	TypeAny Type = iota
+	TypeString
+	TypeInt
+	TypeBool
+	TypeFloat
+	TypeDuration
+	TypeStringSlice
+	TypeIntSlice
+	TypeMap
+	TypeRegexp
+)
+
+// String returns the lower case name of the type.
+func (t Type) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int"
+	case TypeBool:
+		return "bool"
+	case TypeFloat:
+		return "float"
+	case TypeDuration:
+		return "duration"
+	case TypeStringSlice:
+		return "[]string"
+	case TypeIntSlice:
+		return "[]int"
+	case TypeMap:
+		return "map"
+	case TypeRegexp:
+		return "regexp"
+	default:
+		return "any"
+	}
+}
+
+// Meta describes a configuration key: its type, default, documentation and
+// the constraints its value must satisfy.
+type Meta struct {
+	Type     Type
+	Default  any
+	Desc     string
+	Example  any
+	Enum     []any
+	Required bool
+	Secret   bool
+}
+
+// Declare registers metadata for key. A non-nil Default is registered as the
+// key default and Required keys are added to the set checked by Validate.
+func (c *Config) Declare(key string, meta Meta) {
+	if key == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	if c.meta == nil {
+		c.meta = make(map[string]Meta)
+	}
+	c.meta[key] = meta
+	if meta.Required && !containsString(c.required, key) {
+		c.required = append(c.required, key)
+	}
+	if meta.Default != nil {
+		c.defaults[key] = meta.Default
+		c.publishLocked()
+	}
+}
+
+// Metadata returns the metadata declared for key.
+func (c *Config) Metadata(key string) (Meta, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	meta, ok := c.meta[key]
+	return meta, ok
+}
+
+// DeclaredKeys returns the sorted list of keys registered through Declare.
+func (c *Config) DeclaredKeys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]string, 0, len(c.meta))
+	for key := range c.meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// check reports why value does not satisfy the metadata, or an empty string
+// when it does.
+func (m Meta) check(o parseOptions, value any) string {
+	if !m.Type.accepts(o, value) {
+		return fmt.Sprintf("expected %s, got %T", m.Type, value)
+	}
+	if len(m.Enum) == 0 {
+		return ""
+	}
+	for _, allowed := range m.Enum {
+		if stringify(allowed) == stringify(value) {
+			return ""
+		}
+	}
+	options := make([]string, len(m.Enum))
+	for i, allowed := range m.Enum {
+		options[i] = stringify(allowed)
+	}
+	return fmt.Sprintf("%q is not one of %s", stringify(value), strings.Join(options, ", "))
+}
+
+func (t Type) accepts(o parseOptions, value any) bool {
+	var ok bool
+	switch t {
+	case TypeString:
+		ok = true
+	case TypeInt:
+		_, ok = o.toInt(value)
+	case TypeBool:
+		_, ok = o.toBool(value)
+	case TypeFloat:
+		_, ok = o.toFloat64(value)
+	case TypeDuration:
+		_, ok = o.toDuration(value)
+	case TypeStringSlice:
+		ok = toStringSlice(value) != nil
+	case TypeIntSlice:
+		_, ok = o.toIntSlice(value)
+	case TypeMap:
+		_, ok = toStringMap(value)
+	case TypeRegexp:
+		if pattern, isString := value.(string); isString {
+			_, err := regexp.Compile(pattern)
+			ok = err == nil
+		}
+	default:
+		ok = true
+	}
+	return ok
+}
@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeclare(t *testing.T) {
+	c := New()
+	c.Declare("server.port", Meta{Type: TypeInt, Default: 8080, Desc: "listen port"})
+	c.Declare("log_level", Meta{Type: TypeString, Enum: []any{"debug", "info"}, Required: true})
+
+	if got := c.GetInt("server.port"); got != 8080 {
+		t.Fatalf("expected declared default 8080, got %d", got)
+	}
+	meta, ok := c.Metadata("server.port")
+	if !ok || meta.Desc != "listen port" || meta.Type != TypeInt {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+	if keys := c.DeclaredKeys(); len(keys) != 2 || keys[0] != "log_level" {
+		t.Fatalf("unexpected declared keys %#v", keys)
+	}
+
+	var verr *ValidationError
+	if err := c.Validate(); !errors.As(err, &verr) || len(verr.Missing) != 1 {
+		t.Fatalf("expected log_level to be reported missing, got %v", err)
+	}
+
+	c.MergeConfigMap(map[string]any{
+		"server":    map[string]any{"port": "http"},
+		"log_level": "trace",
+	})
+	err := c.Validate()
+	if !errors.As(err, &verr) || len(verr.Invalid) != 2 {
+		t.Fatalf("expected two invalid keys, got %v", err)
+	}
+	if verr.Invalid[0].Key != "log_level" || verr.Invalid[1].Key != "server.port" {
+		t.Fatalf("unexpected invalid keys %+v", verr.Invalid)
+	}
+
+	c.MergeConfigMap(map[string]any{
+		"server":    map[string]any{"port": "9000"},
+		"log_level": "info",
+	})
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected valid configuration, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package conf
+
+import "strings"
+
+// MultiError aggregates the problems found by one operation, such as every
+// failing provider of ReadProviders or every fallback file that could not be
+// read. errors.Is and errors.As inspect each of them.
+type MultiError struct {
+	Errors []error
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the aggregated errors.
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
+// joinErrors returns nil for no errors, the error itself for one and a
+// *MultiError for several.
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return &MultiError{Errors: errs}
+	}
+}
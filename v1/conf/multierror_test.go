@@ -0,0 +1,42 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type failingProvider struct {
+	name string
+	err  error
+}
+
+func (p failingProvider) Name() string { return p.name }
+
+func (p failingProvider) Load(context.Context) (map[string]any, error) { return nil, p.err }
+
+func TestMultiError(t *testing.T) {
+	errA := errors.New("a unreachable")
+	errB := errors.New("b unreachable")
+	c := New()
+	c.AddProvider(failingProvider{name: "a", err: errA})
+	c.AddProvider(failingProvider{name: "b", err: errB})
+	err := c.ReadProviders(context.Background())
+	var me *MultiError
+	if !errors.As(err, &me) || len(me.Errors) != 2 {
+		t.Fatalf("expected a MultiError with 2 errors, got %v", err)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected both provider errors to be inspectable, got %v", err)
+	}
+
+	c = New()
+	c.Require("host", "port")
+	if err := c.Validate(); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected validation error to unwrap to ErrKeyNotFound, got %v", err)
+	}
+	var verr *ValidationError
+	if err := c.Validate(); !errors.As(err, &verr) || len(verr.Unwrap()) != 2 {
+		t.Fatalf("expected one error per missing key, got %v", err)
+	}
+}
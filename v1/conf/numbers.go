@@ -0,0 +1,78 @@
+package conf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numberSuffixes maps the suffixes accepted by human readable numbers to
+// their multipliers. Binary suffixes are listed first so "Ki" wins over "K".
+var numberSuffixes = []struct {
+	suffix string
+	factor float64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"k", 1e3},
+	{"K", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+}
+
+// SetHumanReadableNumbers enables parsing of numbers written with digit
+// separators and magnitude suffixes, such as "1_000_000", "10k", "2M" or
+// "512Mi", in the numeric getters.
+func (c *Config) SetHumanReadableNumbers(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.humanNumbers = enabled
+	c.publishLocked()
+}
+
+func splitNumberSuffix(s string) (string, float64) {
+	for _, sfx := range numberSuffixes {
+		if strings.HasSuffix(s, sfx.suffix) {
+			return strings.TrimSuffix(s, sfx.suffix), sfx.factor
+		}
+	}
+	return s, 1
+}
+
+func parseHumanFloat(s string) (float64, bool) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	num, factor := splitNumberSuffix(s)
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f * factor, true
+}
+
+func parseHumanInt(s string) (int, bool) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "_", "")
+	num, factor := splitNumberSuffix(s)
+	if i, err := strconv.ParseInt(num, 10, 64); err == nil {
+		// Check the bounds before multiplying, so the product cannot wrap.
+		n := int64(factor)
+		if i > math.MaxInt/n || i < math.MinInt/n {
+			return 0, false
+		}
+		return int(i * n), true
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	// math.MaxInt rounds up to 2^63 as a float64, so compare against the
+	// exact bound -math.MinInt instead.
+	scaled := f * factor
+	if scaled != math.Trunc(scaled) || scaled >= -math.MinInt || scaled < math.MinInt {
+		return 0, false
+	}
+	return int(scaled), true
+}
@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestHumanReadableNumbers(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"limit":  "1_000_000",
+		"cache":  "10k",
+		"buffer": "2M",
+		"memory": "512Mi",
+		"ratio":  "1.5k",
+		"bad":    "1.5",
+	})
+
+	if got := c.GetInt("cache"); got != 0 {
+		t.Fatalf("expected suffixes to be ignored unless enabled, got %d", got)
+	}
+
+	c.SetHumanReadableNumbers(true)
+	tests := map[string]int{
+		"limit":  1000000,
+		"cache":  10000,
+		"buffer": 2000000,
+		"memory": 512 << 20,
+		"ratio":  1500,
+	}
+	for key, want := range tests {
+		if got := c.GetInt(key); got != want {
+			t.Fatalf("expected %s=%d, got %d", key, want, got)
+		}
+	}
+	if _, err := c.GetIntE("bad"); err == nil {
+		t.Fatalf("expected fractional value to be rejected as int")
+	}
+	if got := c.GetFloat64("ratio"); got != 1500 {
+		t.Fatalf("expected float 1500, got %f", got)
+	}
+}
+
+func TestParseHumanIntBounds(t *testing.T) {
+	if strconv.IntSize != 64 {
+		t.Skip("bounds are checked for 64-bit ints")
+	}
+	valid := map[string]int{
+		"8388607Ti":    8388607 << 40,
+		"-8388608Ti":   math.MinInt,
+		"-8388608.0Ti": math.MinInt,
+	}
+	for s, want := range valid {
+		if got, ok := parseHumanInt(s); !ok || got != want {
+			t.Fatalf("expected %s=%d, got %d (%v)", s, want, got, ok)
+		}
+	}
+	for _, s := range []string{"8388608Ti", "8388608.0Ti", "9223372036854776k", "-8388609Ti"} {
+		if got, ok := parseHumanInt(s); ok {
+			t.Fatalf("expected %s to overflow, got %d", s, got)
+		}
+	}
+}
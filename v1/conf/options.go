@@ -0,0 +1,196 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Option configures a Config built by New or NewE, so it can be created in
+// one expression and validated at construction:
+//
+//	cfg, err := conf.NewE(
+//		conf.WithConfigName("app"),
+//		conf.WithPaths("/etc/app", "."),
+//		conf.WithEnvPrefix("APP"),
+//	)
+type Option func(*Config) error
+
+// NewE creates a new Config, applies opts in order and checks the result,
+// returning the first problem found.
+func NewE(opts ...Option) (*Config, error) {
+	c := newConfig()
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, global := globalLoader(c.cfgType)
+	if c.cfgType != "" && c.loaders[c.cfgType] == nil && !global {
+		return nil, fmt.Errorf("conf: config type %q: %w", c.cfgType, ErrUnsupportedFormat)
+	}
+	return c, nil
+}
+
+// WithConfigName sets the base name of the config file, see SetConfigName.
+func WithConfigName(name string) Option {
+	return func(c *Config) error {
+		if name == "" {
+			return errors.New("conf: empty config name")
+		}
+		c.SetConfigName(name)
+		return nil
+	}
+}
+
+// WithConfigType sets the format of the config file, see SetConfigType. The
+// format must have a loader by the end of construction.
+func WithConfigType(format string) Option {
+	return func(c *Config) error {
+		c.SetConfigType(format)
+		return nil
+	}
+}
+
+// WithConfigFile sets the config file path, see SetConfigFile.
+func WithConfigFile(path string) Option {
+	return func(c *Config) error {
+		if path == "" {
+			return errors.New("conf: empty config file path")
+		}
+		c.SetConfigFile(path)
+		return nil
+	}
+}
+
+// WithPaths sets the directories searched for the config file, replacing the
+// default search in the working directory.
+func WithPaths(paths ...string) Option {
+	return func(c *Config) error {
+		for _, path := range paths {
+			if path == "" {
+				return errors.New("conf: empty config path")
+			}
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.cfgPaths = append([]string(nil), paths...)
+		return nil
+	}
+}
+
+// WithEnvPrefix sets the prefixes of the environment variables, in priority
+// order, see SetEnvPrefixes.
+func WithEnvPrefix(prefixes ...string) Option {
+	return func(c *Config) error {
+		c.SetEnvPrefixes(prefixes...)
+		return nil
+	}
+}
+
+// WithAutomaticEnv enables automatic environment variable lookup, see
+// AutomaticEnv.
+func WithAutomaticEnv() Option {
+	return func(c *Config) error {
+		c.AutomaticEnv()
+		return nil
+	}
+}
+
+// WithDefaults sets a default for every entry of defaults, see SetDefault.
+func WithDefaults(defaults map[string]any) Option {
+	return func(c *Config) error {
+		for key, value := range defaults {
+			c.SetDefault(key, cloneValue(value))
+		}
+		return nil
+	}
+}
+
+// WithKeyDelimiter sets the key delimiter, see SetKeyDelimiter.
+func WithKeyDelimiter(delim string) Option {
+	return func(c *Config) error {
+		c.SetKeyDelimiter(delim)
+		return nil
+	}
+}
+
+// WithCaseInsensitiveKeys makes keys case-insensitive, see
+// SetKeysCaseInsensitive.
+func WithCaseInsensitiveKeys() Option {
+	return func(c *Config) error {
+		c.SetKeysCaseInsensitive(true)
+		return nil
+	}
+}
+
+// WithPrecedence sets the order in which layers are consulted, see
+// SetPrecedence.
+func WithPrecedence(layers ...Source) Option {
+	return func(c *Config) error {
+		return c.SetPrecedence(layers...)
+	}
+}
+
+// WithLoader registers loader for the extension ext, see RegisterLoader.
+func WithLoader(ext string, loader Loader) Option {
+	return func(c *Config) error {
+		if normalizeExt(ext) == "" || loader == nil {
+			return fmt.Errorf("conf: invalid loader for %q", ext)
+		}
+		c.RegisterLoader(ext, loader)
+		return nil
+	}
+}
+
+// WithProvider registers p, see AddProvider.
+func WithProvider(p Provider, opts ...ProviderOption) Option {
+	return func(c *Config) error {
+		if p == nil {
+			return errors.New("conf: nil provider")
+		}
+		c.AddProvider(p, opts...)
+		return nil
+	}
+}
+
+// WithRequired declares keys that must resolve, see Require.
+func WithRequired(keys ...string) Option {
+	return func(c *Config) error {
+		c.Require(keys...)
+		return nil
+	}
+}
+
+// WithValidator appends fn to the validators, see AddValidator.
+func WithValidator(fn func(candidate Snapshot) error) Option {
+	return func(c *Config) error {
+		if fn == nil {
+			return errors.New("conf: nil validator")
+		}
+		c.AddValidator(fn)
+		return nil
+	}
+}
+
+// WithFaultInjection allows InjectFailure on the Config. It is meant for
+// tests only: without it, a Config built for production cannot be made to
+// fail its loads.
+func WithFaultInjection() Option {
+	return func(c *Config) error {
+		c.faultInjection = true
+		return nil
+	}
+}
+
+// WithLogger sets the logger, see SetLogger.
+func WithLogger(l Logger) Option {
+	return func(c *Config) error {
+		c.SetLogger(l)
+		return nil
+	}
+}
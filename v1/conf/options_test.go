@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte("server:\n  port: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_SERVER_HOST", "example.com")
+	c, err := NewE(
+		WithConfigName("app"),
+		WithConfigType("yaml"),
+		WithPaths(dir),
+		WithEnvPrefix("APP"),
+		WithDefaults(map[string]any{"server.timeout": "5s"}),
+		WithRequired("server.port"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("server.port"); got != 8080 {
+		t.Fatalf("expected port 8080, got %d", got)
+	}
+	if got := c.GetString("server.host"); got != "example.com" {
+		t.Fatalf("expected host from env, got %q", got)
+	}
+	if got := c.GetDuration("server.timeout").String(); got != "5s" {
+		t.Fatalf("expected default timeout 5s, got %s", got)
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected required keys to resolve, got %v", err)
+	}
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	if _, err := NewE(WithPrecedence("nowhere")); err == nil {
+		t.Fatalf("expected an error for an unknown layer")
+	}
+	if _, err := NewE(WithConfigType("hcl")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := NewE(WithConfigType("hcl"), WithLoader("hcl", JSONLoader{})); err != nil {
+		t.Fatalf("expected a loader registered later to satisfy the type, got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected New to panic on an invalid option")
+		}
+	}()
+	New(WithConfigName(""))
+}
@@ -0,0 +1,144 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+)
+
+// Set overrides key with value. Overrides form the highest priority layer,
+// above environment variables, the config file, providers and defaults, and
+// survive reloads. Dotted keys create nested maps, so Set("db.port", 5432)
+// is seen by GetInt("db.port") and by Unmarshal alike. Keys protected by
+// LockKey and defined by the config file keep their file value.
+//
+// The change goes through the validators and emits change events with
+// ChangeSourceRuntime; a rejected change is reported to the logger.
+func (c *Config) Set(key string, value any) {
+	if err := c.set(key, value); err != nil {
+		if errors.Is(err, ErrFrozen) {
+			panic(err)
+		}
+		c.log().Error("conf: failed to set key", "key", key, "error", err)
+	}
+}
+
+// set is Set returning the error of a rejected change.
+func (c *Config) set(key string, value any) error {
+	if key == "" {
+		return nil
+	}
+	return c.update(ChangeSourceRuntime, func() error {
+		s := c.load()
+		key := s.canonical(key)
+		overrides := cloneMap(c.overrides)
+		if overrides == nil {
+			overrides = make(map[string]any)
+		}
+		deletePath(overrides, key, s.delim)
+		setPath(overrides, strings.Split(key, s.delim), normalizeValue(cloneValue(value)))
+		return c.setOverridesLocked(overrides)
+	})
+}
+
+// setOverridesLocked replaces the override layer after running the
+// validators on the resulting configuration, remembering the previous layers
+// for RollbackLast.
+func (c *Config) setOverridesLocked(overrides map[string]any) error {
+	return c.setLayersLocked(c.values, overrides)
+}
+
+// getOverride returns the override of key unless key is locked and defined
+// by the config file.
+func (s *state) getOverride(key string) (any, bool) {
+	v, ok := fetchValue(s.overrides, key, s.delim)
+	if !ok || !matchKeyOrParent(s.locked, key, s.delim) {
+		return v, ok
+	}
+	if _, defined := fetchValue(s.values, key, s.delim); defined {
+		s.locks.report(s.logger, key, ChangeSourceRuntime)
+		return nil, false
+	}
+	return v, true
+}
+
+// overlay returns a copy of base, the value of prefix below the override
+// layer, with every override under prefix applied. An empty prefix overlays
+// the whole layer.
+func (s *state) overlay(prefix string, base map[string]any) map[string]any {
+	sub := s.overrides
+	if prefix != "" {
+		v, _ := fetchValue(s.overrides, prefix, s.delim)
+		sub, _ = v.(map[string]any)
+	}
+	out := cloneMap(base)
+	if len(sub) == 0 {
+		return out
+	}
+	if out == nil {
+		out = make(map[string]any)
+	}
+	flat := make(map[string]any)
+	flattenInto("", sub, flat, s.delim)
+	for rel := range flat {
+		full := rel
+		if prefix != "" {
+			full = prefix + s.delim + rel
+		}
+		if v, ok := s.getOverride(full); ok {
+			setPath(out, strings.Split(rel, s.delim), cloneValue(v))
+		}
+	}
+	return out
+}
+
+// Unset removes key, and everything below it, from the override layer and
+// from the values loaded from the config file or merged at runtime, so the
+// key falls back to the environment, providers or defaults. Parent maps left
+// empty are removed as well. Keys protected by LockKey keep their file value.
+// Like Set, the change goes through the validators and emits change events
+// with ChangeSourceRuntime.
+func (c *Config) Unset(key string) {
+	if key == "" {
+		return
+	}
+	err := c.update(ChangeSourceRuntime, func() error {
+		s := c.load()
+		key := s.canonical(key)
+		overrides := c.overrides
+		if _, ok := fetchValue(overrides, key, s.delim); ok {
+			overrides = cloneMap(overrides)
+			removePath(overrides, key, s.delim)
+		}
+		if _, ok := fetchValue(c.values, key, s.delim); !ok {
+			return c.setOverridesLocked(overrides)
+		}
+		if matchKeyOrParent(c.locked, key, s.delim) {
+			c.locks.report(c.logger, key, ChangeSourceRuntime)
+			return c.setOverridesLocked(overrides)
+		}
+		values := cloneMap(c.values)
+		removePath(values, key, s.delim)
+		return c.setLayersLocked(values, overrides)
+	})
+	if err != nil {
+		if errors.Is(err, ErrFrozen) {
+			panic(err)
+		}
+		c.log().Error("conf: failed to unset key", "key", key, "error", err)
+	}
+}
+
+// removePath deletes key, split at every sep, from m and prunes the parent
+// maps it leaves empty.
+func removePath(m map[string]any, key, sep string) {
+	deletePath(m, key, sep)
+	parts := strings.Split(key, sep)
+	for i := len(parts) - 1; i > 0; i-- {
+		parent := strings.Join(parts[:i], sep)
+		if v, ok := fetchValue(m, parent, sep); ok {
+			if sub, isMap := v.(map[string]any); isMap && len(sub) == 0 {
+				deletePath(m, parent, sep)
+			}
+		}
+	}
+}
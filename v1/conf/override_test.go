@@ -0,0 +1,95 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, []byte("db:\n  host: file\n  port: 5432\nmode: file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DB_HOST", "env")
+	c := New()
+	c.AutomaticEnv()
+	c.SetConfigFile(file)
+	c.LockKey("mode")
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	var events []ChangeEvent
+	c.Subscribe(nil, func(e ChangeEvent) { events = append(events, e) })
+
+	c.Set("db.host", "override")
+	c.Set("db.pool.size", 10)
+	c.Set("mode", "override")
+	if got := c.GetString("db.host"); got != "override" {
+		t.Fatalf("expected override to beat env and file, got %q", got)
+	}
+	if got := c.GetString("mode"); got != "file" {
+		t.Fatalf("expected locked key to keep the file value, got %q", got)
+	}
+	if len(events) != 2 || events[0].Source != ChangeSourceRuntime {
+		t.Fatalf("expected 2 runtime change events, got %+v", events)
+	}
+
+	var cfg struct {
+		DB struct {
+			Host string
+			Port int
+			Pool struct{ Size int }
+		}
+	}
+	if err := c.Unmarshal("", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Host != "override" || cfg.DB.Port != 5432 || cfg.DB.Pool.Size != 10 {
+		t.Fatalf("expected overrides in Unmarshal, got %+v", cfg.DB)
+	}
+
+	if err := os.WriteFile(file, []byte("db:\n  host: changed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("db.host"); got != "override" {
+		t.Fatalf("expected override to survive reloads, got %q", got)
+	}
+}
+
+func TestUnset(t *testing.T) {
+	c := New()
+	c.SetDefault("db.port", 5432)
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"port": 6543, "host": "file"}, "mode": "file"})
+	c.Set("db.port", 7654)
+	c.Set("cache.size", 10)
+
+	var events []ChangeEvent
+	c.Subscribe(nil, func(e ChangeEvent) { events = append(events, e) })
+	c.Unset("db.port")
+	if got := c.GetInt("db.port"); got != 5432 {
+		t.Fatalf("expected the default after unset, got %d", got)
+	}
+	if len(events) != 1 || events[0].Source != ChangeSourceRuntime || events[0].Old != 7654 {
+		t.Fatalf("expected one runtime change event, got %+v", events)
+	}
+	c.Unset("cache.size")
+	if _, err := c.GetStringMapE("cache"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected empty parent maps to be removed")
+	}
+	c.Unset("db")
+	if _, err := c.GetStringE("db.host"); !errors.Is(err, ErrKeyNotFound) || c.GetInt("db.port") != 5432 {
+		t.Fatalf("expected nested keys to be removed with their parent")
+	}
+
+	c.LockKey("mode")
+	c.Unset("mode")
+	if got := c.GetString("mode"); got != "file" {
+		t.Fatalf("expected locked key to be kept, got %q", got)
+	}
+}
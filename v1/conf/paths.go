@@ -0,0 +1,37 @@
+package conf
+
+import "strings"
+
+// The helpers below manipulate nested configuration maps, whose keys are
+// split into levels at a key delimiter. They are exported for providers
+// building or patching the maps they return from Load.
+
+// SetPath stores value at key in m, split into levels at sep, creating the
+// intermediate maps as needed.
+func SetPath(m map[string]any, key, sep string, value any) {
+	setPath(m, strings.Split(key, sep), value)
+}
+
+// DeletePath removes key, split into levels at sep, from m.
+func DeletePath(m map[string]any, key, sep string) {
+	deletePath(m, key, sep)
+}
+
+// MergeMaps merges src into dst, recursing into the maps both hold for the
+// same key, and returns dst, allocated when nil.
+func MergeMaps(dst, src map[string]any) map[string]any {
+	return mergeMaps(dst, src)
+}
+
+// CloneMap returns a deep copy of m.
+func CloneMap(m map[string]any) map[string]any {
+	return cloneMap(m)
+}
+
+// Flatten returns the leaves of m keyed by their path joined with sep.
+// Lists and empty maps are leaves.
+func Flatten(m map[string]any, sep string) map[string]any {
+	out := make(map[string]any)
+	flattenInto("", m, out, sep)
+	return out
+}
@@ -0,0 +1,25 @@
+package conf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathHelpers(t *testing.T) {
+	m := make(map[string]any)
+	SetPath(m, "db/primary/host", "/", "a")
+	SetPath(m, "db/primary/port", "/", 5432)
+	if got := Flatten(m, "/"); !reflect.DeepEqual(got, map[string]any{"db/primary/host": "a", "db/primary/port": 5432}) {
+		t.Fatalf("expected nested values, got %v", got)
+	}
+
+	clone := CloneMap(m)
+	MergeMaps(clone, map[string]any{"db": map[string]any{"primary": map[string]any{"host": "b"}}})
+	DeletePath(clone, "db/primary/port", "/")
+	if got := Flatten(clone, "/"); !reflect.DeepEqual(got, map[string]any{"db/primary/host": "b"}) {
+		t.Fatalf("expected merged and deleted values, got %v", got)
+	}
+	if got := Flatten(m, "/"); len(got) != 2 || got["db/primary/host"] != "a" {
+		t.Fatalf("expected the original to be left untouched, got %v", got)
+	}
+}
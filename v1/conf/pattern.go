@@ -0,0 +1,44 @@
+package conf
+
+import "strings"
+
+// matchPattern reports whether key matches pattern. A "*" in the pattern
+// matches any run of characters, including the key delimiter, so
+// "security.*" matches every key below "security".
+func matchPattern(pattern, key string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == key
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(key, parts[0]) {
+		return false
+	}
+	key = key[len(parts[0]):]
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		idx := strings.Index(key, part)
+		if idx < 0 {
+			return false
+		}
+		key = key[idx+len(part):]
+	}
+	return strings.HasSuffix(key, parts[last])
+}
+
+// matchKeyOrParent reports whether key, or any of its parents split at sep,
+// matches one of the patterns.
+func matchKeyOrParent(patterns []string, key, sep string) bool {
+	for _, pattern := range patterns {
+		for k := key; ; {
+			if matchPattern(pattern, k) {
+				return true
+			}
+			idx := strings.LastIndex(k, sep)
+			if idx < 0 {
+				break
+			}
+			k = k[:idx]
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package conf
+
+import "strings"
+
+// GetPercent returns the value for the key as a fraction. "15%", 15 and 0.15
+// all yield 0.15: values with a percent sign or greater than 1 are treated as
+// percentages, anything else as a fraction already.
+func (c *Config) GetPercent(key string) float64 {
+	f, err := c.GetPercentE(key)
+	c.noteCoercion(err)
+	return f
+}
+
+// GetPercentE is like GetPercent but reports missing keys and values that are
+// not numbers.
+func (c *Config) GetPercentE(key string) (float64, error) {
+	s := c.load()
+	v, ok := s.read(key)
+	if !ok {
+		return 0, notFound(key)
+	}
+	f, ok := s.toPercent(v)
+	if !ok {
+		return 0, &ConversionError{Key: key, Value: v, Target: "percent"}
+	}
+	return f, nil
+}
+
+func (o parseOptions) toPercent(v any) (float64, bool) {
+	if str, ok := v.(string); ok {
+		trimmed := strings.TrimSpace(str)
+		if strings.HasSuffix(trimmed, "%") {
+			f, ok := o.toFloat64(strings.TrimSpace(strings.TrimSuffix(trimmed, "%")))
+			return f / 100, ok
+		}
+		v = trimmed
+	}
+	f, ok := o.toFloat64(v)
+	if !ok {
+		return 0, false
+	}
+	if f > 1 || f < -1 {
+		return f / 100, true
+	}
+	return f, true
+}
@@ -0,0 +1,27 @@
+package conf
+
+import "testing"
+
+func TestGetPercent(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"sign":     "15%",
+		"fraction": 0.15,
+		"whole":    15,
+		"text":     "15",
+		"full":     "100 %",
+		"bad":      "lots",
+	})
+
+	for _, key := range []string{"sign", "fraction", "whole", "text"} {
+		if got := c.GetPercent(key); got != 0.15 {
+			t.Fatalf("expected %s to be 0.15, got %f", key, got)
+		}
+	}
+	if got := c.GetPercent("full"); got != 1 {
+		t.Fatalf("expected full to be 1, got %f", got)
+	}
+	if _, err := c.GetPercentE("bad"); err == nil {
+		t.Fatalf("expected error for non numeric percent")
+	}
+}
@@ -0,0 +1,77 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// ErrInsecureFile is returned, wrapped with the offending file and reason,
+// when PermissionStrict is in effect and a config file holding secret keys is
+// accessible to other users or owned by someone else.
+var ErrInsecureFile = errors.New("insecure config file")
+
+// PermissionCheck controls what happens when a config file holding secret
+// keys has unsafe permissions or ownership.
+type PermissionCheck int
+
+const (
+	// PermissionOff skips the check. It is the default.
+	PermissionOff PermissionCheck = iota
+	// PermissionWarn logs a warning and loads the file anyway.
+	PermissionWarn
+	// PermissionStrict refuses to load the file.
+	PermissionStrict
+)
+
+// SetPermissionCheck enables checking the config file, when it holds keys
+// treated as secret, the way ssh checks private keys: the file must not be
+// accessible to others nor writable by its group, and must be owned by the
+// current user or root. The check only runs on Unix.
+func (c *Config) SetPermissionCheck(mode PermissionCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.permCheck = mode
+}
+
+// checkPermissionsLocked applies the permission check to file, whose decoded
+// contents are values.
+func (c *Config) checkPermissionsLocked(file string, values map[string]any) error {
+	if c.permCheck == PermissionOff {
+		return nil
+	}
+	keys := c.secretKeysLocked(values)
+	if len(keys) == 0 {
+		return nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	reason := insecureFile(info)
+	if reason == "" {
+		return nil
+	}
+	if c.permCheck == PermissionWarn {
+		c.logLocked().Warn("conf: config file with secrets is insecure", "file", file, "reason", reason, "keys", keys)
+		return nil
+	}
+	return fmt.Errorf("%w %s: %s", ErrInsecureFile, file, reason)
+}
+
+// secretKeysLocked returns the sorted keys of values treated as secret,
+// including the lists holding secrets in their entries.
+func (c *Config) secretKeysLocked(values map[string]any) []string {
+	secrets := c.secretSetLocked()
+	flat := make(map[string]any)
+	flattenInto("", values, flat, secrets.delim)
+	var keys []string
+	for key, v := range flat {
+		if secrets.contains(key, v) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package conf
+
+import "os"
+
+// insecureFile reports nothing: permission bits do not describe access on
+// this platform.
+func insecureFile(os.FileInfo) string { return "" }
@@ -0,0 +1,55 @@
+//go:build unix
+
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPermissionCheck(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(path, []byte("db:\n  password: hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	c.SetPermissionCheck(PermissionStrict)
+	if err := c.ReadInConfig(); !errors.Is(err, ErrInsecureFile) {
+		t.Fatalf("expected ErrInsecureFile, got %v", err)
+	}
+
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+	c.SetPermissionCheck(PermissionWarn)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetString("db.password") != "hunter2" || len(logger.Messages()) != 1 {
+		t.Fatalf("expected file to load with one warning, got %v", logger.Messages())
+	}
+
+	c.SetPermissionCheck(PermissionStrict)
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("expected private file to load, got %v", err)
+	}
+
+	plain := filepath.Join(dir, "plain.yaml")
+	if err := os.WriteFile(plain, []byte("port: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c.SetConfigFile(plain)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("expected file without secrets to skip the check, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package conf
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// insecureFile describes why info is unsafe for secrets, or returns "".
+func insecureFile(info os.FileInfo) string {
+	perm := info.Mode().Perm()
+	if perm&0o007 != 0 {
+		return fmt.Sprintf("mode %04o grants access to other users", perm)
+	}
+	if perm&0o020 != 0 {
+		return fmt.Sprintf("mode %04o is group writable", perm)
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	if uid := os.Geteuid(); int(st.Uid) != uid && st.Uid != 0 {
+		return fmt.Sprintf("owned by uid %d instead of %d", st.Uid, uid)
+	}
+	return ""
+}
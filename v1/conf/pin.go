@@ -0,0 +1,91 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// ErrPinned is returned when a change would alter a key pinned by PinOnRead.
+var ErrPinned = errors.New("conf: pinned key cannot change")
+
+// PinOnRead pins the keys matching the patterns, and everything below them,
+// the first time a getter reads them: afterwards any reload, merge or
+// provider update changing the value read is rejected with an error wrapping
+// ErrPinned. It protects identity-like settings such as a cluster ID.
+// Patterns use the same syntax as MarkSecret.
+func (c *Config) PinOnRead(patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	for _, pattern := range patterns {
+		if pattern != "" && !containsString(c.pinPatterns, pattern) {
+			c.pinPatterns = append(c.pinPatterns, pattern)
+		}
+	}
+	c.publishLocked()
+}
+
+// pinSet holds the values of pinned keys as first read. It is shared by every
+// published state.
+type pinSet struct {
+	mu     sync.Mutex
+	values map[string]any
+}
+
+func (p *pinSet) record(key string, value any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.values[key]; ok {
+		return
+	}
+	if p.values == nil {
+		p.values = make(map[string]any)
+	}
+	p.values[key] = cloneValue(value)
+}
+
+func (p *pinSet) empty() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.values) == 0
+}
+
+// check returns an error wrapping ErrPinned when candidate changes the value
+// of a pinned key.
+func (p *pinSet) check(candidate *state) error {
+	// Resolving the candidate may pin keys through computed defaults, so it
+	// runs on a copy of the pinned values.
+	p.mu.Lock()
+	pinned := make(map[string]any, len(p.values))
+	for key, v := range p.values {
+		pinned[key] = v
+	}
+	p.mu.Unlock()
+	keys := make([]string, 0, len(pinned))
+	for key := range pinned {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if v, ok := candidate.get(key); !ok || !reflect.DeepEqual(v, pinned[key]) {
+			return fmt.Errorf("%w: %s", ErrPinned, key)
+		}
+	}
+	return nil
+}
+
+// read resolves key on behalf of a getter, through the interceptors and the
+// transforms, pinning it when it matches a pattern registered with PinOnRead.
+func (s *state) read(key string) (any, bool) {
+	v, ok := s.intercepted(key)
+	if ok && len(s.pinPatterns) > 0 && matchKeyOrParent(s.pinPatterns, key, s.delim) {
+		s.pins.record(key, v)
+	}
+	if ok && len(s.transforms) > 0 {
+		v = s.transformed(key, v)
+	}
+	return v, ok
+}
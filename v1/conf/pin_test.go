@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPinOnRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("cluster:\n  id: a\nname: one\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetConfigFile(path)
+	c.PinOnRead("cluster")
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("cluster:\n  id: b\nname: two\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("expected reload before the first read to be accepted, got %v", err)
+	}
+	if got := c.GetString("cluster.id"); got != "b" {
+		t.Fatalf("expected cluster.id to be b, got %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte("cluster:\n  id: c\nname: three\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); !errors.Is(err, ErrPinned) {
+		t.Fatalf("expected ErrPinned, got %v", err)
+	}
+	if got := c.GetString("cluster.id"); got != "b" {
+		t.Fatalf("expected pinned value to be kept, got %q", got)
+	}
+
+	if err := os.WriteFile(path, []byte("cluster:\n  id: b\nname: four\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatalf("expected reload keeping the pinned value to be accepted, got %v", err)
+	}
+	if got := c.GetString("name"); got != "four" {
+		t.Fatalf("expected unpinned key to change, got %q", got)
+	}
+}
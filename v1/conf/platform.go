@@ -0,0 +1,75 @@
+package conf
+
+import (
+	"runtime"
+	"strings"
+)
+
+// platformsKey is the top-level section holding per-GOOS overlays.
+const platformsKey = "platforms"
+
+// knownGOOS lists the values recognized after "@" in platform-specific keys,
+// so that other keys containing "@" are left alone.
+var knownGOOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true, "js": true,
+	"linux": true, "netbsd": true, "openbsd": true, "plan9": true,
+	"solaris": true, "wasip1": true, "windows": true, "zos": true,
+}
+
+// SetPlatformSections enables platform-conditional configuration in decoded
+// files and payloads. A key suffixed with a GOOS, such as "path@windows",
+// replaces "path" on that platform and is dropped elsewhere; sections are
+// merged rather than replaced. The top-level "platforms" section holds
+// overlays keyed by GOOS, merged over the whole configuration on the
+// matching platform:
+//
+//	cache_dir: /var/cache/app
+//	cache_dir@windows: C:\ProgramData\app\cache
+//	platforms:
+//	  darwin:
+//	    cache_dir: /Library/Caches/app
+func (c *Config) SetPlatformSections(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.platforms = enabled
+}
+
+// applyPlatformsLocked resolves the platform-specific keys of values for the
+// running GOOS.
+func (c *Config) applyPlatformsLocked(values map[string]any) map[string]any {
+	if !c.platforms || values == nil {
+		return values
+	}
+	values = applyPlatformKeys(values, runtime.GOOS)
+	overlays, ok := values[platformsKey].(map[string]any)
+	if !ok {
+		return values
+	}
+	delete(values, platformsKey)
+	if overlay, ok := overlays[runtime.GOOS].(map[string]any); ok {
+		values = mergeMaps(values, overlay)
+	}
+	return values
+}
+
+// applyPlatformKeys replaces, in m and the maps below it, every key with the
+// variant suffixed with goos and drops the variants for other platforms.
+func applyPlatformKeys(m map[string]any, goos string) map[string]any {
+	matched := make(map[string]any)
+	for key, v := range m {
+		if sub, ok := v.(map[string]any); ok {
+			v = applyPlatformKeys(sub, goos)
+			m[key] = v
+		}
+		idx := strings.LastIndex(key, "@")
+		if idx <= 0 || !knownGOOS[key[idx+1:]] {
+			continue
+		}
+		delete(m, key)
+		if key[idx+1:] == goos {
+			matched[key[:idx]] = v
+		}
+	}
+	return mergeMaps(m, matched)
+}
@@ -0,0 +1,49 @@
+package conf
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPlatformSections(t *testing.T) {
+	other := "plan9"
+	if runtime.GOOS == other {
+		other = "linux"
+	}
+	doc := `
+cache_dir: /var/cache/app
+cache_dir@` + other + `: /other/cache
+alerts:
+  ops@corp: pager
+server:
+  port: 80
+server@` + runtime.GOOS + `:
+  host: native
+platforms:
+  ` + runtime.GOOS + `:
+    log:
+      level: debug
+  ` + other + `:
+    log:
+      level: error
+`
+	c := New()
+	c.SetConfigType("yaml")
+	c.SetPlatformSections(true)
+	if err := c.ReadConfig(strings.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("cache_dir"); got != "/var/cache/app" {
+		t.Fatalf("expected base value on %s, got %q", runtime.GOOS, got)
+	}
+	if c.GetInt("server.port") != 80 || c.GetString("server.host") != "native" {
+		t.Fatalf("expected platform section merged into server, got %v", c.AllSettingsFlat())
+	}
+	if _, ok := c.AllSettingsFlat()["platforms."+runtime.GOOS+".log.level"]; ok || c.GetString("log.level") != "debug" {
+		t.Fatalf("expected platform overlay to be applied and removed, got %v", c.AllSettingsFlat())
+	}
+	if c.GetString("alerts.ops@corp") != "pager" {
+		t.Fatalf("expected keys without a platform suffix to be left alone")
+	}
+}
@@ -0,0 +1,111 @@
+package conf
+
+import "fmt"
+
+// defaultPrecedence lists every layer in the order used to complete a custom
+// precedence.
+var defaultPrecedence = []Source{SourceEnv, SourceConfig, SourceProvider, SourceDefault}
+
+// SetPrecedence replaces the order in which layers are consulted, highest
+// priority first, e.g. SetPrecedence(SourceConfig, SourceEnv) lets the config
+// file beat environment variables. Layers left out keep their default
+// relative order after the listed ones. Calling it without arguments
+// restores the default resolution, in which AutomaticEnv variables beat the
+// config file and other variables only beat providers and defaults.
+//
+// The override layer, written by Set, Unset and FlagValue, always sits on top
+// of the order. It may be listed first, as in SetPrecedence(SourceOverride,
+// SourceEnv, SourceConfig, SourceProvider, SourceDefault), where it has no
+// effect, but listing it anywhere else returns an error.
+func (c *Config) SetPrecedence(layers ...Source) error {
+	var order []Source
+	if len(layers) > 0 && layers[0] == SourceOverride {
+		layers = layers[1:]
+	}
+	if len(layers) > 0 {
+		seen := make(map[Source]bool, len(defaultPrecedence))
+		for _, layer := range layers {
+			if layer == SourceOverride {
+				return fmt.Errorf("conf: layer %q is always on top and cannot be reordered", layer)
+			}
+			if !containsSource(defaultPrecedence, layer) {
+				return fmt.Errorf("conf: unknown layer %q", layer)
+			}
+			if seen[layer] {
+				return fmt.Errorf("conf: layer %q listed twice", layer)
+			}
+			seen[layer] = true
+			order = append(order, layer)
+		}
+		for _, layer := range defaultPrecedence {
+			if !seen[layer] {
+				order = append(order, layer)
+			}
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
+	}
+	c.precedence = order
+	c.publishLocked()
+	return nil
+}
+
+func containsSource(list []Source, src Source) bool {
+	for _, s := range list {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the value of key in a single layer. resolveOrdered never asks
+// for the override layer, which resolveCanonical consults first, but
+// ResolveAll does.
+func (s *state) lookup(src Source, key string) (any, bool) {
+	switch src {
+	case SourceOverride:
+		return s.getOverride(key)
+	case SourceEnv:
+		return s.getEnv(key)
+	case SourceConfig:
+		return fetchValue(s.values, key, s.delim)
+	case SourceProvider:
+		if v, ok := fetchValue(s.raised, key, s.delim); ok {
+			return v, true
+		}
+		return fetchValue(s.providers, key, s.delim)
+	case SourceDefault:
+		return s.getDefault(key)
+	}
+	return nil, false
+}
+
+// resolveOrdered resolves key following a precedence set with SetPrecedence.
+// Environment variables still cannot override locked keys defined by the
+// config file.
+func (s *state) resolveOrdered(key string) (any, Source, bool) {
+	for i, src := range s.precedence {
+		// Providers heavier than the config file rank right above it.
+		if src == SourceConfig {
+			if v, ok := fetchValue(s.raised, key, s.delim); ok {
+				return v, SourceProvider, true
+			}
+		}
+		v, ok := s.lookup(src, key)
+		if !ok {
+			continue
+		}
+		if src == SourceEnv && matchKeyOrParent(s.locked, key, s.delim) {
+			if _, defined := fetchValue(s.values, key, s.delim); defined && containsSource(s.precedence[i+1:], SourceConfig) {
+				s.locks.report(s.logger, key, string(SourceEnv))
+				continue
+			}
+		}
+		return v, src, true
+	}
+	return nil, "", false
+}
@@ -0,0 +1,73 @@
+package conf
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type staticProvider map[string]any
+
+func (p staticProvider) Name() string { return "static" }
+
+func (p staticProvider) Load(context.Context) (map[string]any, error) {
+	return cloneMap(p), nil
+}
+
+func TestSetPrecedence(t *testing.T) {
+	t.Setenv("APP_PORT", "3000")
+
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.AutomaticEnv()
+	c.SetDefault("port", 1000)
+	c.SetDefault("name", "default")
+	c.MergeConfigMap(map[string]any{"port": 2000})
+	c.AddProvider(staticProvider{"name": "provider", "port": 4000})
+	if err := c.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 3000 {
+		t.Fatalf("expected env to win by default, got %d", got)
+	}
+
+	if err := c.SetPrecedence(SourceConfig, SourceEnv); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 2000 {
+		t.Fatalf("expected config file to beat env, got %d", got)
+	}
+
+	if err := c.SetPrecedence(SourceDefault); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("name"); got != "default" {
+		t.Fatalf("expected defaults to beat providers, got %q", got)
+	}
+	if got := c.GetInt("port"); got != 1000 {
+		t.Fatalf("expected defaults first, got %d", got)
+	}
+
+	if err := c.SetPrecedence(SourceEnv, SourceEnv); err == nil {
+		t.Fatalf("expected error for duplicated layer")
+	}
+	if err := c.SetPrecedence("flags"); err == nil {
+		t.Fatalf("expected error for unknown layer")
+	}
+	if err := c.SetPrecedence(SourceConfig, SourceOverride); err == nil || !strings.Contains(err.Error(), "always on top") {
+		t.Fatalf("expected the override layer to be rejected, got %v", err)
+	}
+	if err := c.SetPrecedence(SourceOverride, SourceConfig, SourceEnv); err != nil {
+		t.Fatalf("expected a leading override layer to be accepted, got %v", err)
+	}
+	if got := c.GetInt("port"); got != 2000 {
+		t.Fatalf("expected the file to beat env after a leading override layer, got %d", got)
+	}
+
+	if err := c.SetPrecedence(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 3000 {
+		t.Fatalf("expected default precedence to be restored, got %d", got)
+	}
+}
@@ -0,0 +1,30 @@
+package conf
+
+// IsSet reports whether key resolves from any layer: an override, the
+// environment, the config file, a provider or a default.
+func (c *Config) IsSet(key string) bool {
+	_, ok := c.load().intercepted(key)
+	return ok
+}
+
+// InConfig reports whether key is defined by the config file, or by values
+// merged into it at runtime, regardless of what it resolves to. It tells an
+// explicitly configured key apart from one falling back to its default.
+func (c *Config) InConfig(key string) bool {
+	s := c.load()
+	_, _, ok := s.aliased(key, func(k string) (any, Source, bool) {
+		v, ok := fetchValue(s.values, k, s.delim)
+		return v, SourceConfig, ok
+	})
+	return ok
+}
+
+// IsNull reports whether key resolves to an explicit null, such as
+// "key: null" in YAML. Getters return the zero value for such keys and
+// Unmarshal sets the matching pointer fields to nil, while IsSet still
+// reports them as set, which tells "explicitly disabled" apart from "not
+// configured".
+func (c *Config) IsNull(key string) bool {
+	v, ok := c.load().intercepted(key)
+	return ok && v == nil
+}
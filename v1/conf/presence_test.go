@@ -0,0 +1,86 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsSetAndInConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte("db:\n  host: file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DB_USER", "admin")
+	c := New()
+	c.SetDefault("db.port", 5432)
+	c.SetConfigFile(file)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("db.pool", 10)
+
+	for _, key := range []string{"db.host", "db.port", "db.user", "db.pool", "db"} {
+		if !c.IsSet(key) {
+			t.Fatalf("expected %s to be set", key)
+		}
+	}
+	if c.IsSet("db.missing") {
+		t.Fatalf("expected db.missing not to be set")
+	}
+	if !c.InConfig("db.host") || !c.InConfig("db") {
+		t.Fatalf("expected db.host to come from the file")
+	}
+	if c.InConfig("db.port") || c.InConfig("db.user") || c.InConfig("db.pool") {
+		t.Fatalf("expected defaults, env and overrides not to be in the config")
+	}
+}
+
+func TestIsNull(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(file, []byte("cache:\n  ttl: null\n  size: 10\nproxy: null\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetConfigFile(file)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.IsNull("cache.ttl") || !c.IsNull("proxy") {
+		t.Fatalf("expected cache.ttl and proxy to be null")
+	}
+	if c.IsNull("cache.size") || c.IsNull("cache.missing") {
+		t.Fatalf("expected only explicit nulls to be null")
+	}
+	if !c.IsSet("cache.ttl") {
+		t.Fatalf("expected an explicit null to be set")
+	}
+	if v, err := c.GetStringE("proxy"); err != nil || v != "" {
+		t.Fatalf("expected empty string for null, got %q, %v", v, err)
+	}
+	if v, err := c.GetDurationE("cache.ttl"); err != nil || v != 0 {
+		t.Fatalf("expected zero duration for null, got %v, %v", v, err)
+	}
+	if v, err := c.GetStringMapE("proxy"); err != nil || v != nil {
+		t.Fatalf("expected nil map for null, got %v, %v", v, err)
+	}
+
+	ttl, size := 30, 1
+	out := struct {
+		Cache struct {
+			TTL  *int `mapstructure:"ttl"`
+			Size *int `mapstructure:"size"`
+		} `mapstructure:"cache"`
+	}{}
+	out.Cache.TTL, out.Cache.Size = &ttl, &size
+	if err := c.Unmarshal("", &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Cache.TTL != nil {
+		t.Fatalf("expected null ttl to unmarshal to nil, got %d", *out.Cache.TTL)
+	}
+	if out.Cache.Size == nil || *out.Cache.Size != 10 {
+		t.Fatalf("expected size 10, got %v", out.Cache.Size)
+	}
+}
@@ -0,0 +1,240 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ChangeSourceProvider marks changes applied by ReadProviders.
+const ChangeSourceProvider = "provider"
+
+// Provider supplies configuration values from a source other than the config
+// file, such as a remote service. Provider values form their own layer, which
+// overrides the defaults and is overridden by the config file and the
+// environment, unless the provider is heavier than the config file, see
+// ProviderWeight.
+type Provider interface {
+	// Name identifies the provider in errors and logs.
+	Name() string
+	// Load returns the values currently held by the source.
+	Load(ctx context.Context) (map[string]any, error)
+}
+
+// ProviderOption customizes how a provider is layered.
+type ProviderOption func(*providerEntry)
+
+// DefaultConfigWeight is the weight of the config file among the providers
+// unless changed with SetConfigWeight.
+const DefaultConfigWeight = 50
+
+// ProviderWeight sets the priority of a provider, 0 unless set. Providers are
+// merged by ascending weight, so the values of a heavier provider override
+// those of lighter ones, e.g. a system file with weight 10 and a user file
+// with 20. Providers up to the weight of the config file, see
+// SetConfigWeight, rank below it as usual, while heavier ones, such as an
+// emergency override with weight 100, are resolved right above the config
+// file, wherever SetPrecedence places it. Their values are still reported
+// with SourceProvider.
+func ProviderWeight(weight int) ProviderOption {
+	return func(e *providerEntry) {
+		e.weight = weight
+	}
+}
+
+type providerEntry struct {
+	provider Provider
+	weight   int
+}
+
+// AddProvider registers p. Providers are loaded by ReadProviders by ascending
+// weight, then in the order they were added, later ones overriding earlier
+// ones. While WatchProviders is running, p is watched as well and the
+// provider layer is reloaded in the background to include it.
+func (c *Config) AddProvider(p Provider, opts ...ProviderOption) {
+	if p == nil {
+		return
+	}
+	entry := providerEntry{provider: p}
+	for _, opt := range opts {
+		opt(&entry)
+	}
+	c.mu.Lock()
+	if c.frozen {
+		c.mu.Unlock()
+		panic(ErrFrozen)
+	}
+	c.providers = append(c.providers, entry)
+	ctx := c.watchCtx
+	c.mu.Unlock()
+	if ctx == nil || ctx.Err() != nil {
+		return
+	}
+	c.watchProvider(ctx, p)
+	go c.reloadProviders(ctx, p.Name())
+}
+
+// SetConfigWeight sets the weight of the config file among the providers,
+// DefaultConfigWeight unless set: providers with a greater ProviderWeight
+// override the config file. It takes effect at the next ReadProviders.
+func (c *Config) SetConfigWeight(weight int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.configWeight = weight
+}
+
+// ReadProviders loads every registered provider and replaces the provider
+// layer with their merged values. Nothing is applied when a provider fails or
+// a validator rejects the result; when several providers fail, the error is a
+// *MultiError holding one error per provider.
+func (c *Config) ReadProviders(ctx context.Context) error {
+	c.mu.RLock()
+	providers := append([]providerEntry(nil), c.providers...)
+	configWeight := c.configWeight
+	c.mu.RUnlock()
+	sort.SliceStable(providers, func(i, j int) bool {
+		return providers[i].weight < providers[j].weight
+	})
+
+	merged := make(map[string]any)
+	raised := make(map[string]any)
+	var errs []error
+	for _, entry := range providers {
+		name := entry.provider.Name()
+		done := c.hookLoad(name)
+		c.mu.Lock()
+		err := c.takeFaultLocked(name)
+		c.mu.Unlock()
+		var values map[string]any
+		if err == nil {
+			values, err = entry.provider.Load(WithContext(ctx, c))
+		}
+		done(err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provider %s: %w", name, err))
+			continue
+		}
+		if entry.weight > configWeight {
+			mergeMaps(raised, normalizeLoadedMap(values))
+		} else {
+			mergeMaps(merged, normalizeLoadedMap(values))
+		}
+	}
+	if err := joinErrors(errs); err != nil {
+		return err
+	}
+
+	return c.update(ChangeSourceProvider, func() error {
+		return c.setProvidersLocked(merged, raised)
+	})
+}
+
+// setProvidersLocked replaces the provider layer, values, and the providers
+// heavier than the config file, raised, after running the validators on the
+// resulting configuration.
+func (c *Config) setProvidersLocked(values, raised map[string]any) error {
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
+	}
+	if err := c.runValidatorsLocked(c.values, c.overrides, values, raised); err != nil {
+		return err
+	}
+	// c.mu was released while the validators ran.
+	if err := c.checkFrozenLocked(); err != nil {
+		return err
+	}
+	c.providerValues = values
+	c.raisedValues = raised
+	c.publishLocked()
+	return nil
+}
+
+// FileProvider is a Provider reading a config file, which lets several files
+// be layered with ProviderWeight.
+type FileProvider struct {
+	Path    string
+	Decoder Decoder
+	// Optional makes a missing file provide no values instead of failing.
+	Optional bool
+}
+
+// NewFileProvider returns a provider reading path and decoding it with dec,
+// usually the Config it is added to, according to its extension.
+func NewFileProvider(path string, dec Decoder) *FileProvider {
+	return &FileProvider{Path: path, Decoder: dec}
+}
+
+// Name returns the path of the file.
+func (p *FileProvider) Name() string {
+	return p.Path
+}
+
+// Load reads and decodes the file.
+func (p *FileProvider) Load(context.Context) (map[string]any, error) {
+	if p.Decoder == nil {
+		return nil, errors.New("no decoder set")
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		if p.Optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return p.Decoder.Decode(data, filepath.Ext(p.Path))
+}
+
+// ProviderWatcher is implemented by providers able to detect changes at
+// their source. Watch blocks until ctx is done, calling notify after every
+// change.
+type ProviderWatcher interface {
+	Watch(ctx context.Context, notify func()) error
+}
+
+// WatchProviders starts watching every registered provider implementing
+// ProviderWatcher and reloads the provider layer, through ReadProviders,
+// whenever one of them reports a change. Providers added later are watched
+// too. Watching stops when ctx is done; errors are reported to the logger and
+// the OnError hooks.
+func (c *Config) WatchProviders(ctx context.Context) {
+	c.mu.Lock()
+	c.watchCtx = ctx
+	providers := append([]providerEntry(nil), c.providers...)
+	c.mu.Unlock()
+	for _, entry := range providers {
+		c.watchProvider(ctx, entry.provider)
+	}
+}
+
+// watchProvider watches p until ctx is done when it implements
+// ProviderWatcher.
+func (c *Config) watchProvider(ctx context.Context, p Provider) {
+	w, ok := p.(ProviderWatcher)
+	if !ok {
+		return
+	}
+	name := p.Name()
+	c.runHooks(func(h Hooks) { h.OnWatchStart(name) })
+	go func() {
+		defer c.runHooks(func(h Hooks) { h.OnWatchStop(name) })
+		err := w.Watch(WithContext(ctx, c), func() { c.reloadProviders(ctx, name) })
+		if err != nil && ctx.Err() == nil {
+			c.log().Error("conf: provider watch stopped", "provider", name, "error", err)
+			c.reportError(err)
+		}
+	}()
+}
+
+// reloadProviders runs ReadProviders on behalf of the named provider,
+// reporting failures instead of returning them.
+func (c *Config) reloadProviders(ctx context.Context, name string) {
+	if err := c.ReadProviders(ctx); err != nil {
+		c.log().Error("conf: failed to reload providers", "provider", name, "error", err)
+		c.reportError(err)
+	}
+}
@@ -0,0 +1,113 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProviderWeights(t *testing.T) {
+	dir := t.TempDir()
+	system := filepath.Join(dir, "system.yaml")
+	user := filepath.Join(dir, "user.toml")
+	if err := os.WriteFile(system, []byte("port: 1\nname: system\nlog: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(user, []byte("port = 2\nname = \"user\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.AddProvider(staticProvider{"port": 100}, ProviderWeight(100))
+	c.AddProvider(NewFileProvider(user, c), ProviderWeight(20))
+	c.AddProvider(NewFileProvider(system, c), ProviderWeight(10))
+	c.AddProvider(&FileProvider{Path: filepath.Join(dir, "missing.yaml"), Decoder: c, Optional: true})
+	if err := c.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetInt("port"); got != 100 {
+		t.Fatalf("expected heaviest provider to win, got %d", got)
+	}
+	if got := c.GetString("name"); got != "user" {
+		t.Fatalf("expected user file to override system file, got %q", got)
+	}
+	if got := c.GetString("log"); got != "info" {
+		t.Fatalf("expected system file value, got %q", got)
+	}
+
+	c.MergeConfigMap(map[string]any{"port": 8080, "name": "file"})
+	if got := c.GetInt("port"); got != 100 {
+		t.Fatalf("expected a provider heavier than the config file to win, got %d", got)
+	}
+	if got := c.GetString("name"); got != "file" {
+		t.Fatalf("expected the config file to beat lighter providers, got %q", got)
+	}
+	for _, res := range c.ResolveAll() {
+		if res.Key == "port" && res.Source != SourceProvider {
+			t.Fatalf("expected port to come from a provider, got %s", res.Source)
+		}
+	}
+	c.SetConfigWeight(200)
+	if err := c.ReadProviders(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 8080 {
+		t.Fatalf("expected the config file to win once heavier, got %d", got)
+	}
+
+	c.AddProvider(NewFileProvider(filepath.Join(dir, "missing.yaml"), c))
+	if err := c.ReadProviders(context.Background()); err == nil {
+		t.Fatalf("expected error for missing required file")
+	}
+}
+
+func TestHotSwapAfterWatchStarted(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.cfgx")
+	if err := os.WriteFile(file, []byte(`{"port": 8080}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetConfigFile(file)
+	if err := c.ReadInConfig(); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+	reloaded := make(chan struct{}, 1)
+	c.OnConfigChange(func() { reloaded <- struct{}{} })
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.RegisterLoader("cfgx", JSONLoader{})
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the watcher to reload with the new loader")
+	}
+	if got := c.GetInt("port"); got != 8080 {
+		t.Fatalf("expected port from the reloaded file, got %d", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	c.Subscribe(KeyPrefix("region"), func(ChangeEvent) { changed <- struct{}{} })
+	c.WatchProviders(ctx)
+	extra := filepath.Join(dir, "extra.json")
+	if err := os.WriteFile(extra, []byte(`{"region": "eu"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c.AddProvider(NewFileProvider(extra, c))
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected a provider added while watching to be loaded")
+	}
+	if got := c.GetString("region"); got != "eu" {
+		t.Fatalf("expected region from the added provider, got %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package conf
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// maxCachedRegexps bounds the patterns a Config keeps compiled. Reaching it
+// empties the cache, so patterns replaced by reloads do not pile up.
+const maxCachedRegexps = 64
+
+// regexpCache holds the patterns compiled by GetRegexp by source, so a
+// pattern is compiled once and a reload changing it compiles the new one on
+// the next read.
+type regexpCache struct {
+	mu sync.Mutex
+	m  map[string]*regexp.Regexp
+}
+
+// compile compiles pattern through the cache.
+func (rc *regexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if re, ok := rc.m[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if rc.m == nil || len(rc.m) >= maxCachedRegexps {
+		rc.m = make(map[string]*regexp.Regexp)
+	}
+	rc.m[pattern] = re
+	return re, nil
+}
+
+// GetRegexp returns the value for the key compiled as a regular expression,
+// or nil when the key is missing or the pattern is invalid. Declaring the key
+// with TypeRegexp makes Validate reject invalid patterns when the
+// configuration is loaded.
+func (c *Config) GetRegexp(key string) *regexp.Regexp {
+	re, err := c.GetRegexpE(key)
+	c.noteCoercion(err)
+	return re
+}
+
+// GetRegexpE is like GetRegexp but reports missing keys and invalid
+// patterns.
+func (c *Config) GetRegexpE(key string) (*regexp.Regexp, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return nil, notFound(key)
+	}
+	pattern, ok := v.(string)
+	if !ok {
+		return nil, &ConversionError{Key: key, Value: v, Target: TypeRegexp.String()}
+	}
+	re, err := c.regexps.compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", &ConversionError{Key: key, Value: v, Target: TypeRegexp.String()}, err)
+	}
+	return re, nil
+}
@@ -0,0 +1,46 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGetRegexp(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"routes": map[string]any{"api": `^/api/v\d+/`, "bad": "([a-z"}})
+
+	re := c.GetRegexp("routes.api")
+	if re == nil || !re.MatchString("/api/v2/users") {
+		t.Fatalf("expected compiled pattern, got %v", re)
+	}
+	if c.GetRegexp("routes.api") != re {
+		t.Fatalf("expected compiled pattern to be cached")
+	}
+	c.MergeConfigMap(map[string]any{"routes": map[string]any{"api": `^/v\d+/`}})
+	if !c.GetRegexp("routes.api").MatchString("/v1/") {
+		t.Fatalf("expected reloaded pattern to be compiled")
+	}
+
+	var convErr *ConversionError
+	if _, err := c.GetRegexpE("routes.bad"); !errors.As(err, &convErr) {
+		t.Fatalf("expected conversion error, got %v", err)
+	}
+	c.Declare("routes.bad", Meta{Type: TypeRegexp})
+	if err := c.Validate(); err == nil {
+		t.Fatalf("expected Validate to reject the invalid pattern")
+	}
+}
+
+func TestRegexpCacheBounded(t *testing.T) {
+	c := New()
+	for i := 0; i < 3*maxCachedRegexps; i++ {
+		c.Set("pattern", fmt.Sprintf("^v%d$", i))
+		if c.GetRegexp("pattern") == nil {
+			t.Fatalf("expected pattern %d to compile", i)
+		}
+	}
+	if n := len(c.regexps.m); n > maxCachedRegexps {
+		t.Fatalf("expected at most %d cached patterns, got %d", maxCachedRegexps, n)
+	}
+}
@@ -0,0 +1,34 @@
+package conf
+
+// notifyReload runs the OnConfigChange callback, publishes TopicReload and
+// delivers the change events computed against before, the state preceding
+// the reload. It must be called without holding c.mu.
+func (c *Config) notifyReload(before *state) {
+	c.mu.RLock()
+	callback := c.onChange
+	c.mu.RUnlock()
+	if callback != nil {
+		callback()
+	}
+	c.events.Publish(Event{Topic: TopicReload})
+	c.dispatchChanges(diffStates(before, c.load(), ChangeSourceFile))
+}
+
+// OnChangeUnmarshal decodes the subtree at key into a fresh T after every
+// successful reload and hands it to fn, replacing the glue code that would
+// otherwise call Unmarshal from OnConfigChange. Decoding errors are logged
+// and skip fn.
+//
+//	conf.OnChangeUnmarshal(cfg, "server", func(srv ServerConfig) {
+//		server.Apply(srv)
+//	})
+func OnChangeUnmarshal[T any](c *Config, key string, fn func(T)) {
+	c.events.Handle(TopicReload, func(Event) {
+		var out T
+		if err := c.Unmarshal(key, &out); err != nil {
+			c.log().Error("conf: failed to decode reloaded config", "key", key, "error", err)
+			return
+		}
+		fn(out)
+	})
+}
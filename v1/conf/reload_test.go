@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestOnChangeUnmarshal(t *testing.T) {
+	tmp, err := os.CreateTemp("", "cfg*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := os.WriteFile(tmp.Name(), []byte("server:\n  port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(tmp.Name())
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	type serverConfig struct {
+		Port int `mapstructure:"port"`
+	}
+	received := make(chan serverConfig, 1)
+	OnChangeUnmarshal(c, "server", func(srv serverConfig) {
+		received <- srv
+	})
+	if err := c.WatchConfig(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := os.WriteFile(tmp.Name(), []byte("server:\n  port: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case srv := <-received:
+		if srv.Port != 2 {
+			t.Fatalf("expected decoded port 2, got %d", srv.Port)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected decoded config after reload")
+	}
+}
@@ -0,0 +1,71 @@
+package conf
+
+import "encoding/json"
+
+// Resolution records how a key was resolved: its final value, the layer it
+// came from and the values of the lower priority layers it shadows. Values
+// of secret keys are redacted.
+type Resolution struct {
+	Key      string          `json:"key"`
+	Value    any             `json:"value"`
+	Source   Source          `json:"source"`
+	Secret   bool            `json:"secret,omitempty"`
+	Shadowed []ShadowedValue `json:"shadowed,omitempty"`
+}
+
+// ShadowedValue is a value defined by a layer but overridden by a higher
+// priority one.
+type ShadowedValue struct {
+	Source Source `json:"source"`
+	Value  any    `json:"value"`
+}
+
+// ResolveAll returns the resolution of every known key, sorted by key, with
+// the shadowed layers listed from the highest priority to the lowest. The
+// report is deterministic for a given configuration and environment, so it
+// can be attached to deployment audit trails, see ExportResolutions.
+func (c *Config) ResolveAll() []Resolution {
+	s := c.load()
+	secrets := c.secretSet()
+	order := s.order()
+	var out []Resolution
+	for _, key := range s.keys() {
+		v, src, ok := s.resolve(key)
+		if !ok {
+			continue
+		}
+		res := Resolution{Key: key, Value: v, Source: src, Secret: secrets.has(key)}
+		for _, layer := range order {
+			if layer == src {
+				continue
+			}
+			if sv, ok := s.lookup(layer, key); ok {
+				res.Shadowed = append(res.Shadowed, ShadowedValue{Source: layer, Value: sv})
+			}
+		}
+		if res.Secret {
+			res.Value = redactedValue
+			for i := range res.Shadowed {
+				res.Shadowed[i].Value = redactedValue
+			}
+		}
+		out = append(out, res)
+	}
+	return out
+}
+
+// ExportResolutions returns the report of ResolveAll as indented JSON.
+func (c *Config) ExportResolutions() ([]byte, error) {
+	return json.MarshalIndent(c.ResolveAll(), "", "  ")
+}
+
+// order returns the layers in the order they are consulted.
+func (s *state) order() []Source {
+	switch {
+	case s.precedence != nil:
+		return append([]Source{SourceOverride}, s.precedence...)
+	case s.automatic:
+		return []Source{SourceOverride, SourceEnv, SourceConfig, SourceProvider, SourceDefault}
+	}
+	return []Source{SourceOverride, SourceConfig, SourceEnv, SourceProvider, SourceDefault}
+}
@@ -0,0 +1,44 @@
+package conf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestResolveAll(t *testing.T) {
+	t.Setenv("APP_DB_HOST", "env-host")
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.SetDefault("db.host", "localhost")
+	c.SetDefault("db.port", 5432)
+	c.SetDefault("db.password", "default-secret")
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"host": "file-host", "password": "file-secret"}})
+	c.Set("db.port", 6432)
+
+	got := c.ResolveAll()
+	want := []Resolution{
+		{Key: "db.host", Value: "file-host", Source: SourceConfig, Shadowed: []ShadowedValue{
+			{Source: SourceEnv, Value: "env-host"},
+			{Source: SourceDefault, Value: "localhost"},
+		}},
+		{Key: "db.password", Value: redactedValue, Source: SourceConfig, Secret: true, Shadowed: []ShadowedValue{
+			{Source: SourceDefault, Value: redactedValue},
+		}},
+		{Key: "db.port", Value: 6432, Source: SourceOverride, Shadowed: []ShadowedValue{
+			{Source: SourceDefault, Value: 5432},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	data, err := c.ExportResolutions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Resolution
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded) != 3 || decoded[0].Source != SourceConfig {
+		t.Fatalf("expected the report to round-trip through JSON, got %s, %v", data, err)
+	}
+}
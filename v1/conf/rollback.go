@@ -0,0 +1,41 @@
+package conf
+
+import "errors"
+
+// ErrNoRollback is returned by RollbackLast when there is no previous
+// configuration to restore.
+var ErrNoRollback = errors.New("conf: no previous configuration to roll back to")
+
+// ChangeSourceRollback marks changes applied by RollbackLast.
+const ChangeSourceRollback = "rollback"
+
+// RollbackLast restores the config values and overrides that were in effect
+// before the most recent reload, merge, Set or Unset and emits the resulting
+// change events. Only one
+// level is kept: rolling back twice in a row returns ErrNoRollback.
+func (c *Config) RollbackLast() error {
+	c.commitMu.Lock()
+	c.mu.Lock()
+	if err := c.checkFrozenLocked(); err != nil {
+		c.mu.Unlock()
+		c.commitMu.Unlock()
+		return err
+	}
+	if !c.hasPrev {
+		c.mu.Unlock()
+		c.commitMu.Unlock()
+		return ErrNoRollback
+	}
+	before := c.load()
+	c.values = c.prevValues
+	c.overrides = c.prevOverrides
+	c.prevValues, c.prevOverrides = nil, nil
+	c.hasPrev = false
+	c.publishLocked()
+	after := c.load()
+	c.mu.Unlock()
+	c.commitMu.Unlock()
+
+	c.dispatchChanges(diffStates(before, after, ChangeSourceRollback))
+	return nil
+}
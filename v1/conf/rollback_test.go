@@ -0,0 +1,71 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRollbackLast(t *testing.T) {
+	c := New()
+	if err := c.RollbackLast(); !errors.Is(err, ErrNoRollback) {
+		t.Fatalf("expected ErrNoRollback on fresh config, got %v", err)
+	}
+
+	c.SetConfigType("yaml")
+	if err := c.ReadConfig(strings.NewReader("port: 1\nhost: a\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadConfig(strings.NewReader("port: 2\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []ChangeEvent
+	c.Subscribe(nil, func(ev ChangeEvent) {
+		events = append(events, ev)
+	})
+	if err := c.RollbackLast(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 1 {
+		t.Fatalf("expected port 1 after rollback, got %d", got)
+	}
+	if len(events) != 1 || events[0].Key != "port" || events[0].Source != ChangeSourceRollback {
+		t.Fatalf("unexpected rollback events %+v", events)
+	}
+	if err := c.RollbackLast(); !errors.Is(err, ErrNoRollback) {
+		t.Fatalf("expected a single level of rollback, got %v", err)
+	}
+}
+
+func TestRollbackLastRestoresOverrides(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"port": 1, "host": "a"})
+	c.Set("port", 2)
+	if err := c.RollbackLast(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 1 {
+		t.Fatalf("expected the Set to be rolled back, got %d", got)
+	}
+
+	c.Unset("host")
+	if err := c.RollbackLast(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("host"); got != "a" {
+		t.Fatalf("expected the Unset to be rolled back, got %q", got)
+	}
+
+	c.Set("port", 3)
+	c.MergeConfigMap(map[string]any{"host": "b"})
+	if err := c.RollbackLast(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 3 {
+		t.Fatalf("expected the override to survive rolling back a merge, got %d", got)
+	}
+	if got := c.GetString("host"); got != "a" {
+		t.Fatalf("expected host a after rollback, got %q", got)
+	}
+}
@@ -0,0 +1,65 @@
+package conf
+
+import (
+	"context"
+	"time"
+)
+
+// ReloadSchedule re-reads the config file and the providers on the schedule
+// given by a standard five-field cron expression, such as "0 */6 * * *", or a
+// descriptor like "@hourly" or "@every 10m". It suits sources that cannot be
+// watched, such as HTTP endpoints or object stores. Reloads deliver the same
+// callbacks and change events as the file watcher, and failures go to the
+// logger and the OnError hooks. Calling it again replaces the previous
+// schedule; Close stops it.
+func (c *Config) ReloadSchedule(spec string) error {
+	next, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	if c.stopSchedule != nil {
+		c.stopSchedule()
+	}
+	c.stopSchedule = cancel
+	c.mu.Unlock()
+
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(next(time.Now())))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				c.scheduledReload(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// scheduledReload runs one scheduled reload of the config file, when one is
+// configured, and of the providers.
+func (c *Config) scheduledReload(ctx context.Context) {
+	c.mu.RLock()
+	hasFile := c.file != "" || c.cfgName != ""
+	hasProviders := len(c.providers) > 0
+	c.mu.RUnlock()
+	if hasFile {
+		before := c.load()
+		if err := c.ReadInConfig(); err != nil {
+			c.log().Error("conf: scheduled reload failed", "error", err)
+			c.reportError(err)
+		} else {
+			c.notifyReload(before)
+		}
+	}
+	if hasProviders {
+		if err := c.ReadProviders(ctx); err != nil && ctx.Err() == nil {
+			c.log().Error("conf: scheduled provider reload failed", "error", err)
+			c.reportError(err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package conf
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingProvider struct {
+	loads atomic.Int32
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Load(context.Context) (map[string]any, error) {
+	n := p.loads.Add(1)
+	return map[string]any{"generation": int(n)}, nil
+}
+
+func TestReloadSchedule(t *testing.T) {
+	if err := New().ReloadSchedule("not a schedule"); err == nil {
+		t.Fatalf("expected error for an invalid cron expression")
+	}
+
+	p := &countingProvider{}
+	c := New()
+	c.AddProvider(p)
+	changed := make(chan ChangeEvent, 4)
+	c.Subscribe(KeyPrefix("generation"), func(ev ChangeEvent) { changed <- ev })
+	if err := c.ReloadSchedule("@every 1s"); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	select {
+	case ev := <-changed:
+		if ev.Source != ChangeSourceProvider {
+			t.Fatalf("expected provider change, got %q", ev.Source)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("expected a scheduled reload")
+	}
+	c.Close()
+	loads := p.loads.Load()
+	time.Sleep(1500 * time.Millisecond)
+	if p.loads.Load() != loads {
+		t.Fatalf("expected Close to stop the schedule")
+	}
+}
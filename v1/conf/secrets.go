@@ -0,0 +1,162 @@
+package conf
+
+import "strings"
+
+// redactedValue replaces secret values in human readable output.
+const redactedValue = "******"
+
+// sensitiveWords flag keys that are treated as secret even when they were not
+// marked explicitly.
+var sensitiveWords = []string{"password", "passwd", "secret", "token", "apikey", "api_key", "private_key", "credential"}
+
+// MarkSecret flags keys whose values must never be printed. Patterns may use
+// "*" wildcards, and marking a key also covers everything below it. Keys
+// whose name contains words like "password", "secret" or "token" are always
+// treated as secret.
+func (c *Config) MarkSecret(patterns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pattern := range patterns {
+		if pattern != "" && !containsString(c.secrets, pattern) {
+			c.secrets = append(c.secrets, pattern)
+		}
+	}
+}
+
+// IsSecret reports whether the value of key is treated as secret.
+func (c *Config) IsSecret(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isSecretLocked(key)
+}
+
+func (c *Config) isSecretLocked(key string) bool {
+	return isSecretKey(key, c.secrets, c.delimLocked(), func(k string) bool { return c.meta[k].Secret })
+}
+
+// secretSet is a copy of what makes keys secret, taken under c.mu so that
+// values can be resolved and masked without holding the lock.
+type secretSet struct {
+	patterns []string
+	declared map[string]bool
+	delim    string
+}
+
+// secretSet copies the secret patterns and the keys declared secret.
+func (c *Config) secretSet() secretSet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.secretSetLocked()
+}
+
+func (c *Config) secretSetLocked() secretSet {
+	set := secretSet{
+		patterns: append([]string(nil), c.secrets...),
+		declared: make(map[string]bool),
+		delim:    c.delimLocked(),
+	}
+	for key, meta := range c.meta {
+		if meta.Secret {
+			set.declared[key] = true
+		}
+	}
+	return set
+}
+
+// has reports whether the value of key is treated as secret.
+func (s secretSet) has(key string) bool {
+	return isSecretKey(key, s.patterns, s.delim, func(k string) bool { return s.declared[k] })
+}
+
+// join returns the key of child under key, child itself at the top level.
+func (s secretSet) join(key, child string) string {
+	if key == "" {
+		return child
+	}
+	return key + s.delim + child
+}
+
+// redact returns v, the value of key, with its secret parts replaced by
+// mask: v itself when key is secret, otherwise the secret entries of the maps
+// it holds, however deeply nested in maps and lists. The elements of a list
+// are reached through the key of the list, so "db.users.password" covers
+// the password of every entry of db.users. v is not modified.
+func (s secretSet) redact(key string, v any, mask string) any {
+	if s.has(key) {
+		return mask
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = s.redact(s.join(key, k), item, mask)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = s.redact(key, item, mask)
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = s.redact(key, item, mask)
+		}
+		return out
+	}
+	return v
+}
+
+// contains reports whether v, the value of key, holds a secret anywhere.
+func (s secretSet) contains(key string, v any) bool {
+	if s.has(key) {
+		return true
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if s.contains(s.join(key, k), item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range val {
+			if s.contains(key, item) {
+				return true
+			}
+		}
+	case []map[string]any:
+		for _, item := range val {
+			if s.contains(key, item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSecretKey reports whether key matches one of patterns, has itself or a
+// parent declared secret, or contains a sensitive word.
+func isSecretKey(key string, patterns []string, delim string, declared func(string) bool) bool {
+	if matchKeyOrParent(patterns, key, delim) {
+		return true
+	}
+	for k := key; ; {
+		if declared(k) {
+			return true
+		}
+		idx := strings.LastIndex(k, delim)
+		if idx < 0 {
+			break
+		}
+		k = k[:idx]
+	}
+	lower := strings.ToLower(key)
+	for _, word := range sensitiveWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package conf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Suffixes appended to the name of a file or URL to locate its detached
+// signature when none is set: minisign signatures are looked up under the
+// name minisign gives them, others under DefaultSignatureSuffix.
+const (
+	DefaultSignatureSuffix  = ".sig"
+	MinisignSignatureSuffix = ".minisig"
+)
+
+// ErrBadSignature is returned when a configuration payload does not match
+// its detached signature.
+var ErrBadSignature = errors.New("conf: invalid configuration signature")
+
+// Verifier checks a detached signature over a configuration payload.
+type Verifier interface {
+	Verify(data, sig []byte) error
+}
+
+type ed25519Verifier struct {
+	key ed25519.PublicKey
+}
+
+// NewEd25519Verifier returns a Verifier accepting raw ed25519 signatures
+// made with the private key matching key, either as 64 bytes or base64
+// encoded.
+func NewEd25519Verifier(key ed25519.PublicKey) Verifier {
+	return ed25519Verifier{key: key}
+}
+
+func (v ed25519Verifier) Verify(data, sig []byte) error {
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil {
+			return ErrBadSignature
+		}
+		sig = decoded
+	}
+	if len(sig) != ed25519.SignatureSize || !ed25519.Verify(v.key, data, sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+type minisignVerifier struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// NewMinisignVerifier returns a Verifier for signatures made with minisign,
+// given the public key either as the contents of a minisign.pub file or as
+// its base64 line. Both legacy and prehashed signatures are accepted, and the
+// trusted comment is verified too.
+func NewMinisignVerifier(publicKey string) (Verifier, error) {
+	raw, err := minisignPayload(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return nil, errors.New("conf: unsupported minisign public key")
+	}
+	v := minisignVerifier{key: ed25519.PublicKey(raw[10:])}
+	copy(v.keyID[:], raw[2:10])
+	return v, nil
+}
+
+func (v minisignVerifier) Verify(data, sig []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(sig)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("%w: malformed minisign signature", ErrBadSignature)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed minisign signature", ErrBadSignature)
+	}
+	if !bytes.Equal(raw[2:10], v.keyID[:]) {
+		return fmt.Errorf("%w: signed with another key", ErrBadSignature)
+	}
+	signature := raw[10:]
+	switch string(raw[:2]) {
+	case "Ed":
+	case "ED":
+		sum := blake2b.Sum512(data)
+		data = sum[:]
+	default:
+		return fmt.Errorf("%w: unsupported minisign algorithm", ErrBadSignature)
+	}
+	if !ed25519.Verify(v.key, data, signature) {
+		return ErrBadSignature
+	}
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return fmt.Errorf("%w: malformed minisign signature", ErrBadSignature)
+	}
+	comment := strings.TrimSuffix(strings.TrimPrefix(lines[2], "trusted comment: "), "\r")
+	if !ed25519.Verify(v.key, append(append([]byte(nil), signature...), comment...), global) {
+		return fmt.Errorf("%w: trusted comment", ErrBadSignature)
+	}
+	return nil
+}
+
+// minisignPayload decodes the base64 line of a minisign key, skipping the
+// optional untrusted comment.
+func minisignPayload(s string) ([]byte, error) {
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "untrusted comment:") {
+			continue
+		}
+		return base64.StdEncoding.DecodeString(line)
+	}
+	return nil, errors.New("conf: empty minisign key")
+}
+
+// signatureSuffix returns the suffix of the detached signatures checked by
+// v.
+func signatureSuffix(v Verifier) string {
+	if _, ok := v.(minisignVerifier); ok {
+		return MinisignSignatureSuffix
+	}
+	return DefaultSignatureSuffix
+}
+
+// SetSignatureVerifier requires the config file to be signed: before it is
+// decoded, its detached signature is read from "<file>.minisig" for a
+// minisign verifier and "<file>.sig" otherwise, and checked with v, and a
+// missing or invalid signature fails the read. A nil v disables the check.
+func (c *Config) SetSignatureVerifier(v Verifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.verifier = v
+}
+
+// SetSignatureSuffix sets the suffix appended to the config file name to
+// read its detached signature, overriding the one of the verifier. An empty
+// suffix restores it.
+func (c *Config) SetSignatureSuffix(suffix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sigSuffix = suffix
+}
+
+// verifyFileLocked checks the detached signature of file, whose contents
+// are data.
+func (c *Config) verifyFileLocked(file string, data []byte) error {
+	if c.verifier == nil {
+		return nil
+	}
+	suffix := c.sigSuffix
+	if suffix == "" {
+		suffix = signatureSuffix(c.verifier)
+	}
+	sig, err := c.readFileLocked(file + suffix)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s is not signed", ErrBadSignature, file)
+		}
+		return err
+	}
+	if err := c.verifier.Verify(data, sig); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
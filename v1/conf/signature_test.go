@@ -0,0 +1,119 @@
+package conf
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestSignedConfigFile(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	data := []byte("port: 8080\n")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	c.SetSignatureVerifier(NewEd25519Verifier(pub))
+	if err := c.ReadInConfig(); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected unsigned file to be rejected, got %v", err)
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data))
+	if err := os.WriteFile(path+".sig", []byte(sig+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); err != nil || c.GetInt("port") != 8080 {
+		t.Fatalf("expected signed file to load, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte("port: 6666\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadInConfig(); !errors.Is(err, ErrBadSignature) || c.GetInt("port") != 8080 {
+		t.Fatalf("expected tampered file to be rejected, got %v", err)
+	}
+}
+
+func TestMinisignHTTPProvider(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyID := []byte("12345678")
+	pubKey := "untrusted comment: minisign public key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), pub...))
+	payload := []byte(`{"feature": true}`)
+	sum := blake2b.Sum512(payload)
+	signature := ed25519.Sign(priv, sum[:])
+	comment := "timestamp:1700000000"
+	global := ed25519.Sign(priv, append(append([]byte(nil), signature...), comment...))
+	sigFile := "untrusted comment: signature\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("ED"), keyID...), signature...)) + "\n" +
+		"trusted comment: " + comment + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+
+	served := payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/config.json.minisig" {
+			w.Write([]byte(sigFile))
+			return
+		}
+		w.Write(served)
+	}))
+	defer srv.Close()
+
+	v, err := NewMinisignVerifier(pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	p := NewHTTPProvider(srv.URL+"/config.json", c)
+	p.Verifier = v
+	c.AddProvider(p)
+	if err := c.ReadProviders(context.Background()); err != nil || !c.GetBool("feature") {
+		t.Fatalf("expected signed payload to load, got %v", err)
+	}
+	served = []byte(`{"feature": false}`)
+	if err := c.ReadProviders(context.Background()); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected tampered payload to be rejected, got %v", err)
+	}
+}
+
+func TestSignatureSuffix(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	data := []byte("port: 8080\n")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data))
+	if err := os.WriteFile(path+".asc", []byte(sig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New()
+	c.SetConfigFile(path)
+	c.SetSignatureVerifier(NewEd25519Verifier(pub))
+	if err := c.ReadInConfig(); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected the signature to be looked up under .sig, got %v", err)
+	}
+	c.SetSignatureSuffix(".asc")
+	if err := c.ReadInConfig(); err != nil || c.GetInt("port") != 8080 {
+		t.Fatalf("expected the signature to be read from .asc, got %v", err)
+	}
+}
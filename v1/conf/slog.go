@@ -0,0 +1,38 @@
+package conf
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// LogValue implements slog.LogValuer. The effective configuration is emitted
+// as nested groups with secret values masked, so a Config can be passed to
+// slog directly:
+//
+//	slog.Info("loaded config", "config", cfg)
+func (c *Config) LogValue() slog.Value {
+	return c.secretSet().logGroup("", c.load().settings())
+}
+
+func (secrets secretSet) logGroup(prefix string, m map[string]any) slog.Value {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		key := k
+		if prefix != "" {
+			key = prefix + secrets.delim + k
+		}
+		if secrets.has(key) {
+			attrs = append(attrs, slog.String(k, redactedValue))
+		} else if nested, ok := m[k].(map[string]any); ok {
+			attrs = append(attrs, slog.Attr{Key: k, Value: secrets.logGroup(key, nested)})
+		} else {
+			attrs = append(attrs, slog.Any(k, secrets.redact(key, m[k], redactedValue)))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"server":   map[string]any{"host": "localhost", "port": 8080},
+		"database": map[string]any{"password": "hunter2"},
+		"db": map[string]any{"users": []any{
+			map[string]any{"name": "a", "password": "s3cr3t"},
+		}},
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger.Info("loaded config", "config", c)
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "s3cr3t") {
+		t.Fatalf("expected password to be redacted, got %s", out)
+	}
+	for _, want := range []string{"config.database.password=******", "config.server.host=localhost", "config.server.port=8080"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in %s", want, out)
+		}
+	}
+}
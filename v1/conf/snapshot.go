@@ -0,0 +1,161 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrRejected is wrapped by the error returned when a validator vetoes a
+// candidate configuration.
+var ErrRejected = errors.New("conf: configuration rejected")
+
+// Snapshot is a read-only view of an effective configuration. It is handed to
+// validators as the candidate being applied and can be taken from a Config
+// at any time.
+type Snapshot struct {
+	s *state
+}
+
+// Snapshot returns a view of the configuration currently in effect.
+func (c *Config) Snapshot() Snapshot {
+	return Snapshot{s: c.load()}
+}
+
+// Get returns the raw value for key.
+func (s Snapshot) Get(key string) (any, bool) {
+	if s.s == nil {
+		return nil, false
+	}
+	return s.s.get(key)
+}
+
+// GetString returns the string value for key.
+func (s Snapshot) GetString(key string) string {
+	if v, ok := s.Get(key); ok {
+		return stringify(v)
+	}
+	return ""
+}
+
+// GetInt returns the int value for key.
+func (s Snapshot) GetInt(key string) int {
+	if v, ok := s.Get(key); ok {
+		i, _ := s.s.toInt(v)
+		return i
+	}
+	return 0
+}
+
+// GetBool returns the boolean value for key.
+func (s Snapshot) GetBool(key string) bool {
+	if v, ok := s.Get(key); ok {
+		b, _ := s.s.toBool(v)
+		return b
+	}
+	return false
+}
+
+// GetDuration returns the time.Duration value for key.
+func (s Snapshot) GetDuration(key string) time.Duration {
+	if v, ok := s.Get(key); ok {
+		d, _ := s.s.toDuration(v)
+		return d
+	}
+	return 0
+}
+
+// Keys returns every key known to the snapshot, flattened and sorted.
+func (s Snapshot) Keys() []string {
+	if s.s == nil {
+		return nil
+	}
+	return s.s.keys()
+}
+
+// Settings returns the effective configuration as a nested map.
+func (s Snapshot) Settings() map[string]any {
+	if s.s == nil {
+		return map[string]any{}
+	}
+	return s.s.settings()
+}
+
+// Layer returns the values held by a single layer as a nested map, ignoring
+// the others: the defaults, including computed ones, the config file, the
+// providers, the overrides, or the environment variables set for known keys.
+func (s Snapshot) Layer(src Source) map[string]any {
+	out := make(map[string]any)
+	if s.s == nil {
+		return out
+	}
+	switch src {
+	case SourceConfig:
+		return mergeMaps(out, cloneMap(s.s.values))
+	case SourceProvider:
+		return mergeMaps(mergeMaps(out, cloneMap(s.s.providers)), cloneMap(s.s.raised))
+	case SourceOverride:
+		return mergeMaps(out, cloneMap(s.s.overrides))
+	}
+	for _, key := range s.s.keys() {
+		if v, ok := s.s.lookup(src, key); ok {
+			setPath(out, strings.Split(key, s.s.delim), cloneValue(v))
+		}
+	}
+	return out
+}
+
+// AddValidator appends fn to the chain of validators that every candidate
+// configuration must pass before a read, reload or merge is applied.
+// Every validator runs, in registration order, and any error vetoes the
+// change. Each error is reported wrapping both ErrRejected and the
+// validator's error, several of them in a *MultiError, together with the
+// violations of keys pinned with PinOnRead.
+// Validators run without the configuration locked, so they may read the
+// Config, but they must not change it.
+func (c *Config) AddValidator(fn func(candidate Snapshot) error) {
+	if fn == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.validators = append(c.validators, fn)
+}
+
+// runValidatorsLocked runs the pin check and the validators against the
+// configuration made of the given layers. The caller must hold c.mu for
+// writing and c.commitMu, which keeps the layers from changing meanwhile:
+// c.mu is released while the candidate is checked, since computed defaults
+// and validators may call back into c, and the check is repeated when
+// another change was published in between.
+func (c *Config) runValidatorsLocked(values, overrides, providers, raised map[string]any) error {
+	for {
+		if len(c.validators) == 0 && c.pins.empty() {
+			return nil
+		}
+		validators := append([]func(Snapshot) error(nil), c.validators...)
+		candidate := c.candidateLocked(values, overrides, providers, raised)
+		published := c.current.Load()
+		c.mu.Unlock()
+		err := c.checkCandidate(validators, candidate)
+		c.mu.Lock()
+		if c.current.Load() == published {
+			return err
+		}
+	}
+}
+
+// checkCandidate runs the pin check and validators against candidate.
+func (c *Config) checkCandidate(validators []func(Snapshot) error, candidate *state) error {
+	var errs []error
+	if err := c.pins.check(candidate); err != nil {
+		errs = append(errs, err)
+	}
+	for i, fn := range validators {
+		if err := fn(Snapshot{s: candidate}); err != nil {
+			errs = append(errs, fmt.Errorf("%w by validator %d: %w", ErrRejected, i+1, err))
+		}
+	}
+	return joinErrors(errs)
+}
@@ -0,0 +1,143 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidatorsVetoCandidate(t *testing.T) {
+	c := New()
+	c.SetConfigType("yaml")
+	if err := c.ReadConfig(strings.NewReader("port: 8080\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	c.AddValidator(func(candidate Snapshot) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	reason := errors.New("port must be above 1024")
+	c.AddValidator(func(candidate Snapshot) error {
+		calls = append(calls, "second")
+		if candidate.GetInt("port") <= 1024 {
+			return reason
+		}
+		return nil
+	})
+	other := errors.New("port must be even")
+	c.AddValidator(func(candidate Snapshot) error {
+		calls = append(calls, "third")
+		if candidate.GetInt("port")%2 != 0 {
+			return other
+		}
+		return nil
+	})
+
+	err := c.ReadConfig(strings.NewReader("port: 80\n"))
+	if !errors.Is(err, ErrRejected) || !errors.Is(err, reason) {
+		t.Fatalf("expected rejection wrapping the reason, got %v", err)
+	}
+	if strings.Join(calls, ",") != "first,second,third" {
+		t.Fatalf("expected every validator to run, got %v", calls)
+	}
+
+	calls = nil
+	err = c.ReadConfig(strings.NewReader("port: 81\n"))
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 || !errors.Is(err, reason) || !errors.Is(err, other) {
+		t.Fatalf("expected both rejections to be reported, got %v", err)
+	}
+	if got := c.GetInt("port"); got != 8080 {
+		t.Fatalf("expected rejected change not to apply, got %d", got)
+	}
+
+	c.MergeConfigMap(map[string]any{"port": 9090})
+	if got := c.Snapshot().GetInt("port"); got != 9090 {
+		t.Fatalf("expected accepted merge to apply, got %d", got)
+	}
+}
+
+func TestValidatorsRunWithoutLock(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, []byte("port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]func(c *Config) error{
+		"Set":            func(c *Config) error { c.Set("port", 2); return nil },
+		"Unset":          func(c *Config) error { c.Unset("port"); return nil },
+		"MergeConfigMap": func(c *Config) error { c.MergeConfigMap(map[string]any{"port": 2}); return nil },
+		"ReadConfig":     func(c *Config) error { return c.ReadConfig(strings.NewReader("port: 2\n")) },
+		"MergeConfig":    func(c *Config) error { return c.MergeConfig(strings.NewReader("port: 2\n")) },
+		"ReadInConfig":   func(c *Config) error { return c.ReadInConfig() },
+		"MergeInConfig":  func(c *Config) error { return c.MergeInConfig() },
+		"ReadHelmValues": func(c *Config) error { return c.ReadHelmValues([]string{file}) },
+		"ApplyStaged":    func(c *Config) error { return c.ApplyStaged(map[string]any{"port": 2}, 0) },
+		"ReadProviders": func(c *Config) error {
+			c.AddProvider(&countingProvider{})
+			return c.ReadProviders(context.Background())
+		},
+	}
+	for name, apply := range paths {
+		t.Run(name, func(t *testing.T) {
+			c := New()
+			c.SetConfigType("yaml")
+			c.SetConfigFile(file)
+			c.SetDefault("zone", "a")
+			c.SetDefaultFunc("region", func(view *Config) any {
+				// A computed default may read the Config it belongs to,
+				// including pinned keys.
+				c.IsSecret("region")
+				view.GetString("zone")
+				return "eu"
+			})
+			c.PinOnRead("region", "zone")
+			c.GetString("region")
+			c.AddValidator(func(candidate Snapshot) error {
+				c.IsSecret("port")
+				if candidate.GetString("region") != "eu" {
+					return errors.New("unexpected region")
+				}
+				return nil
+			})
+			done := make(chan error, 1)
+			go func() { done <- apply(c) }()
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatal(err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("expected validators to run without the config lock")
+			}
+		})
+	}
+}
+
+func TestSnapshotLayer(t *testing.T) {
+	t.Setenv("PORT", "7070")
+	c := New()
+	c.SetDefault("db.port", 5432)
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"port": 6543}})
+	c.Set("db.pool", 10)
+
+	s := c.Snapshot()
+	if got := s.Layer(SourceDefault)["db"].(map[string]any)["port"]; got != 5432 {
+		t.Fatalf("expected default layer, got %v", got)
+	}
+	if got := s.Layer(SourceConfig)["db"].(map[string]any)["port"]; got != 6543 {
+		t.Fatalf("expected config layer, got %v", got)
+	}
+	if got := s.Layer(SourceOverride)["db"].(map[string]any)["pool"]; got != 10 {
+		t.Fatalf("expected override layer, got %v", got)
+	}
+	if got := s.Layer(SourceEnv); len(got) != 0 {
+		t.Fatalf("expected no env values for known keys, got %v", got)
+	}
+}
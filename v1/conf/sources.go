@@ -0,0 +1,114 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SourceInfo describes one place the configuration is read from.
+type SourceInfo struct {
+	// Source is the layer the values end up in.
+	Source Source
+	// Name is the file path, the provider name or the environment prefix,
+	// empty for unprefixed variables.
+	Name string
+	// Found reports whether a file exists. It is always true for providers
+	// and the environment.
+	Found bool
+	// Used reports whether ReadInConfig reads this file, either because it
+	// is the config file or because it is the fallback standing in for a
+	// missing one. It is always true for providers and the environment.
+	Used bool
+	// Fallback marks files set with SetFallbackConfigFile.
+	Fallback bool
+}
+
+// Sources lists every config file candidate in search order, with whether it
+// exists and which one is read, then the fallback files, the providers in
+// load order and, when environment variables are consulted, one entry per
+// prefix in priority order, including the fallback prefixes set with
+// SetEnvPrefixes and the unprefixed names allowed by AllowUnprefixedEnv. Nothing is read or decoded, so it answers which file would be
+// loaded without loading it.
+func (c *Config) Sources() []SourceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var out []SourceInfo
+	used := false
+	for _, file := range c.fileCandidatesLocked() {
+		found := fileExists(file)
+		out = append(out, SourceInfo{Source: SourceConfig, Name: file, Found: found, Used: found && !used})
+		used = used || found
+	}
+	for _, file := range c.fallbacks {
+		found := fileExists(file)
+		out = append(out, SourceInfo{Source: SourceConfig, Name: file, Found: found, Used: found && !used, Fallback: true})
+		used = used || found
+	}
+
+	providers := append([]providerEntry(nil), c.providers...)
+	sort.SliceStable(providers, func(i, j int) bool {
+		return providers[i].weight < providers[j].weight
+	})
+	for _, entry := range providers {
+		out = append(out, SourceInfo{Source: SourceProvider, Name: entry.provider.Name(), Found: true, Used: true})
+	}
+	if c.automatic || c.envPrefix != "" || len(c.envBindings) > 0 || len(c.envFallback) > 0 || len(c.envBare) > 0 {
+		prefixes := append([]string{c.envPrefix}, c.envFallback...)
+		if len(c.envBare) > 0 {
+			prefixes = append(prefixes, "")
+		}
+		seen := make(map[string]bool, len(prefixes))
+		for _, prefix := range prefixes {
+			if !seen[prefix] {
+				seen[prefix] = true
+				out = append(out, SourceInfo{Source: SourceEnv, Name: prefix, Found: true, Used: true})
+			}
+		}
+	}
+	return out
+}
+
+// ConfigFileUsed returns the config file the values were last read from by
+// ReadInConfig, which is a fallback file when the primary one could not be
+// read, or an empty string when no file has been read yet.
+func (c *Config) ConfigFileUsed() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fileUsed
+}
+
+// SearchedPaths returns the files the last ReadInConfig checked, in order:
+// the search path candidates up to the one found, or the file set with
+// SetConfigFile, then the fallback files it tried.
+func (c *Config) SearchedPaths() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.searched...)
+}
+
+// fileCandidatesLocked returns the config files findConfigFileLocked looks
+// at, in order. The caller must hold c.mu.
+func (c *Config) fileCandidatesLocked() []string {
+	if c.file != "" {
+		return []string{c.file}
+	}
+	if c.cfgName == "" {
+		return nil
+	}
+	files := make([]string, 0, len(c.cfgPaths))
+	for _, p := range c.cfgPaths {
+		name := filepath.Join(p, c.cfgName)
+		if c.cfgType != "" {
+			name += "." + c.cfgType
+		}
+		files = append(files, name)
+	}
+	return files
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
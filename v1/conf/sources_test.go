@@ -0,0 +1,90 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSources(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(second, "app.yaml"), []byte("port: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetConfigName("app")
+	c.SetConfigType("yaml")
+	c.AddConfigPath(first)
+	c.AddConfigPath(second)
+	c.SetEnvPrefix("APP")
+	c.AddProvider(NewFileProvider(filepath.Join(first, "extra.json"), c))
+
+	sources := c.Sources()
+	if len(sources) != 5 {
+		t.Fatalf("expected 3 files, a provider and the env, got %+v", sources)
+	}
+	if sources[0].Name != filepath.Join(".", "app.yaml") || sources[0].Found {
+		t.Fatalf("expected the working directory to be searched first, got %+v", sources[0])
+	}
+	if sources[1].Found || !sources[2].Found || !sources[2].Used || sources[2].Name != filepath.Join(second, "app.yaml") {
+		t.Fatalf("expected the second path to be used, got %+v", sources[1:3])
+	}
+	if sources[3].Source != SourceProvider || sources[4].Source != SourceEnv || sources[4].Name != "APP" {
+		t.Fatalf("expected provider and env prefix, got %+v", sources[3:])
+	}
+}
+
+func TestConfigFileUsedAndSearchedPaths(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	fallback := filepath.Join(second, "last-good.json")
+	if err := os.WriteFile(fallback, []byte(`{"port": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.SetConfigName("app")
+	c.SetConfigType("yaml")
+	c.AddConfigPath(first)
+	if err := c.ReadInConfig(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist, got %v", err)
+	}
+	want := []string{filepath.Join(".", "app.yaml"), filepath.Join(first, "app.yaml")}
+	if got := c.SearchedPaths(); !reflect.DeepEqual(got, want) || c.ConfigFileUsed() != "" {
+		t.Fatalf("expected searched paths %v, got %v", want, got)
+	}
+
+	c.SetFallbackConfigFile(fallback)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.ConfigFileUsed(); got != fallback {
+		t.Fatalf("expected the fallback to be reported, got %q", got)
+	}
+	if got := c.SearchedPaths(); len(got) != 3 || got[2] != fallback {
+		t.Fatalf("expected the fallback among the searched paths, got %v", got)
+	}
+}
+
+func TestSourcesEnvPrefixes(t *testing.T) {
+	c := New()
+	c.SetEnvPrefixes("MYAPP", "APP")
+	c.AllowUnprefixedEnv("http.proxy")
+
+	var names []string
+	for _, src := range c.Sources() {
+		if src.Source == SourceEnv {
+			names = append(names, src.Name)
+		}
+	}
+	if want := []string{"MYAPP", "APP", ""}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected env sources %q, got %q", want, names)
+	}
+
+	t.Setenv("APP_PORT", "1")
+	c.SetEnvPrefix("NEW")
+	c.AutomaticEnv()
+	if c.IsSet("port") {
+		t.Fatalf("expected SetEnvPrefix to drop the fallback prefixes")
+	}
+}
@@ -0,0 +1,174 @@
+package conf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoStaged is returned by Confirm and Abort when no staged apply is
+	// pending.
+	ErrNoStaged = errors.New("conf: no staged configuration pending")
+	// ErrStagePending is returned by ApplyStaged while a previous staged
+	// apply has been neither confirmed nor aborted.
+	ErrStagePending = errors.New("conf: a staged configuration is already pending")
+)
+
+// ChangeSourceStaged marks changes applied by ApplyStaged.
+const ChangeSourceStaged = "staged"
+
+// stagedApply remembers the values layer before and after a pending
+// ApplyStaged.
+type stagedApply struct {
+	before map[string]any
+	after  map[string]any
+	timer  *time.Timer
+}
+
+// ApplyStaged merges data into the configuration tentatively, like
+// MergeConfigMap, so that the application can observe its effect - error
+// rates, health checks - before calling Confirm to keep it or Abort to
+// restore the values it changed. When timeout is positive
+// and neither happens in time, the change is aborted automatically. Changes
+// are reported with ChangeSourceStaged, and the rollback with
+// ChangeSourceRollback.
+func (c *Config) ApplyStaged(data map[string]any, timeout time.Duration) error {
+	normalized := normalizeLoadedMap(cloneMap(data))
+	return c.update(ChangeSourceStaged, func() error {
+		if c.staged != nil {
+			return ErrStagePending
+		}
+		before := c.values
+		if err := c.mergeConfigMapLocked(c.stripLockedLocked(normalized, ChangeSourceStaged)); err != nil {
+			return err
+		}
+		stage := &stagedApply{before: before, after: c.values}
+		if timeout > 0 {
+			stage.timer = time.AfterFunc(timeout, func() {
+				if c.abortStage(stage) == nil {
+					c.log().Warn("conf: staged configuration not confirmed in time, rolled back", "timeout", timeout)
+				}
+			})
+		}
+		c.staged = stage
+		return nil
+	})
+}
+
+// Confirm keeps the configuration applied by ApplyStaged.
+func (c *Config) Confirm() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.staged == nil {
+		return ErrNoStaged
+	}
+	if c.staged.timer != nil {
+		c.staged.timer.Stop()
+	}
+	c.staged = nil
+	return nil
+}
+
+// Abort restores the values the pending ApplyStaged changed and emits the
+// resulting change events. Keys changed since by another writer keep their
+// current value.
+func (c *Config) Abort() error {
+	c.mu.RLock()
+	stage := c.staged
+	c.mu.RUnlock()
+	if stage == nil {
+		return ErrNoStaged
+	}
+	return c.abortStage(stage)
+}
+
+// abortStage rolls back stage if it is still the pending one.
+func (c *Config) abortStage(stage *stagedApply) error {
+	c.commitMu.Lock()
+	c.mu.Lock()
+	if c.staged != stage {
+		c.mu.Unlock()
+		c.commitMu.Unlock()
+		return ErrNoStaged
+	}
+	if err := c.checkFrozenLocked(); err != nil {
+		c.mu.Unlock()
+		c.commitMu.Unlock()
+		return err
+	}
+	if stage.timer != nil {
+		stage.timer.Stop()
+	}
+	c.staged = nil
+	before := c.load()
+	c.prevValues = c.values
+	c.prevOverrides = c.overrides
+	c.hasPrev = true
+	c.values = stage.undo(c.values, c.delimLocked())
+	c.publishLocked()
+	after := c.load()
+	c.mu.Unlock()
+	c.commitMu.Unlock()
+
+	c.dispatchChanges(diffStates(before, after, ChangeSourceRollback))
+	return nil
+}
+
+// undo returns a copy of values in which the keys changed by the stage get
+// back their value from before it, or are removed when the stage added them.
+// Keys whose value differs from the one the stage set were changed by
+// another writer since, and are left alone.
+func (stage *stagedApply) undo(values map[string]any, sep string) map[string]any {
+	before := make(map[string]any)
+	after := make(map[string]any)
+	current := make(map[string]any)
+	flattenInto("", stage.before, before, sep)
+	flattenInto("", stage.after, after, sep)
+	flattenInto("", values, current, sep)
+
+	out := cloneMap(values)
+	if out == nil {
+		out = make(map[string]any)
+	}
+	for key, v := range after {
+		old, existed := before[key]
+		if existed && reflect.DeepEqual(old, v) {
+			continue
+		}
+		if cur, ok := current[key]; !ok || !reflect.DeepEqual(cur, v) {
+			continue
+		}
+		if existed {
+			setPath(out, strings.Split(key, sep), cloneValue(old))
+		} else {
+			removePath(out, key, sep)
+		}
+	}
+	// Keys the stage replaced with a scalar higher up the tree.
+	for key, old := range before {
+		if _, ok := after[key]; ok {
+			continue
+		}
+		if _, ok := fetchValue(out, key, sep); !ok && !leafAbove(out, key, sep) {
+			setPath(out, strings.Split(key, sep), cloneValue(old))
+		}
+	}
+	return out
+}
+
+// leafAbove reports whether a parent of key holds a value other than a map.
+func leafAbove(m map[string]any, key, sep string) bool {
+	parts := strings.Split(key, sep)
+	for i := 1; i < len(parts); i++ {
+		v, ok := fetchValue(m, strings.Join(parts[:i], sep), sep)
+		if !ok {
+			return false
+		}
+		if _, isMap := v.(map[string]any); !isMap {
+			return true
+		}
+	}
+	return false
+}
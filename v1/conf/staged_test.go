@@ -0,0 +1,95 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplyStaged(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"pool": map[string]any{"size": 10}})
+
+	if err := c.ApplyStaged(map[string]any{"pool": map[string]any{"size": 50}}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetInt("pool.size") != 50 {
+		t.Fatalf("expected staged value to be live")
+	}
+	if err := c.ApplyStaged(map[string]any{"pool": map[string]any{"size": 60}}, 0); !errors.Is(err, ErrStagePending) {
+		t.Fatalf("expected ErrStagePending, got %v", err)
+	}
+	if err := c.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetInt("pool.size") != 10 {
+		t.Fatalf("expected Abort to restore 10, got %d", c.GetInt("pool.size"))
+	}
+
+	if err := c.ApplyStaged(map[string]any{"pool": map[string]any{"size": 20}}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Confirm(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Abort(); !errors.Is(err, ErrNoStaged) || c.GetInt("pool.size") != 20 {
+		t.Fatalf("expected confirmed value to stay, got %v", err)
+	}
+
+	rolledBack := make(chan ChangeEvent, 1)
+	c.Subscribe(nil, func(ev ChangeEvent) {
+		if ev.Source == ChangeSourceRollback {
+			rolledBack <- ev
+		}
+	})
+	if err := c.ApplyStaged(map[string]any{"pool": map[string]any{"size": 99}}, 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-rolledBack:
+		if ev.Key != "pool.size" || c.GetInt("pool.size") != 20 {
+			t.Fatalf("expected automatic rollback to 20, got %v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected staged change to time out")
+	}
+}
+
+func TestAbortKeepsConcurrentChanges(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"pool": map[string]any{"size": 10, "idle": 2}, "mode": map[string]any{"a": 1}})
+
+	staged := map[string]any{
+		"pool": map[string]any{"size": 50, "idle": 4, "max": 100},
+		"mode": "fast",
+	}
+	if err := c.ApplyStaged(staged, 0); err != nil {
+		t.Fatal(err)
+	}
+	c.MergeConfigMap(map[string]any{"pool": map[string]any{"idle": 8}, "region": "eu"})
+	if err := c.Abort(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.GetInt("pool.size"); got != 10 {
+		t.Fatalf("expected the staged size to be undone, got %d", got)
+	}
+	if c.IsSet("pool.max") {
+		t.Fatalf("expected the staged key to be removed")
+	}
+	if got := c.GetInt("mode.a"); got != 1 {
+		t.Fatalf("expected the map replaced by the stage to come back, got %d", got)
+	}
+	if got := c.GetInt("pool.idle"); got != 8 {
+		t.Fatalf("expected a later change to a staged key to be kept, got %d", got)
+	}
+	if got := c.GetString("region"); got != "eu" {
+		t.Fatalf("expected a later change to be kept, got %q", got)
+	}
+	if err := c.RollbackLast(); err != nil {
+		t.Fatalf("expected the abort to be rolled back, got %v", err)
+	}
+	if got := c.GetInt("pool.size"); got != 50 {
+		t.Fatalf("expected rolling back the abort to reapply the stage, got %d", got)
+	}
+}
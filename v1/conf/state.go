@@ -0,0 +1,312 @@
+package conf
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// state is an immutable snapshot of everything needed to resolve a key. A new
+// state is built by the writer holding Config.mu and swapped in atomically, so
+// readers never observe a partially applied reload and never block on one.
+type state struct {
+	view      *Config
+	defaults  map[string]any
+	computed  map[string]func(*Config) any
+	values    map[string]any
+	overrides map[string]any
+	providers map[string]any
+	// raised holds the providers heavier than the config file, resolved
+	// right above it.
+	raised      map[string]any
+	envPrefix   string
+	envBindings map[string]string
+	envFallback []string
+	envBare     []string
+	automatic   bool
+	structured  bool
+	locked      []string
+	locks       *lockGuard
+	logger      Logger
+	pinPatterns []string
+	pins        *pinSet
+	precedence  []Source
+	coercion    CoercionPolicy
+	intercept   []Interceptor
+	transforms  []transform
+	aliases     []keyAlias
+	delim       string
+	fold        bool
+	parseOptions
+}
+
+// publishLocked rebuilds the effective state from the mutable fields and makes
+// it visible to readers. The caller must hold c.mu for writing.
+func (c *Config) publishLocked() {
+	if c.foldKeys {
+		c.foldLayersLocked()
+	}
+	c.current.Store(c.stateLocked(c.values))
+}
+
+// stateLocked builds a state from the current settings and the given values
+// layer. The caller must hold c.mu.
+func (c *Config) stateLocked(values map[string]any) *state {
+	return c.candidateLocked(values, c.overrides, c.providerValues, c.raisedValues)
+}
+
+// candidateLocked builds a state from the current settings and the given
+// values, override and provider layers, raised holding the providers heavier
+// than the config file. The caller must hold c.mu.
+func (c *Config) candidateLocked(values, overrides, providers, raised map[string]any) *state {
+	bindings := make(map[string]string, len(c.envBindings))
+	for k, v := range c.envBindings {
+		bindings[k] = v
+	}
+	computed := make(map[string]func(*Config) any, len(c.defaultFuncs))
+	for k, fn := range c.defaultFuncs {
+		computed[k] = fn
+	}
+	s := &state{
+		defaults:     cloneMap(c.defaults),
+		computed:     computed,
+		values:       cloneMap(values),
+		overrides:    cloneMap(overrides),
+		providers:    cloneMap(providers),
+		raised:       cloneMap(raised),
+		envPrefix:    c.envPrefix,
+		envBindings:  bindings,
+		envFallback:  c.envFallback,
+		envBare:      c.envBare,
+		automatic:    c.automatic,
+		structured:   c.structuredEnv,
+		locked:       append([]string(nil), c.locked...),
+		locks:        &c.locks,
+		logger:       c.logger,
+		pinPatterns:  append([]string(nil), c.pinPatterns...),
+		pins:         &c.pins,
+		precedence:   c.precedence,
+		coercion:     c.coercion,
+		intercept:    c.interceptors,
+		transforms:   c.transforms,
+		aliases:      c.sortedAliasesLocked(),
+		delim:        c.delimLocked(),
+		fold:         c.foldKeys,
+		parseOptions: c.parseOptions,
+	}
+	if c.foldKeys {
+		// Candidate layers may not have been published, and folded, yet.
+		s.values = foldKeys(s.values)
+		s.overrides = foldKeys(s.overrides)
+		s.providers = foldKeys(s.providers)
+		s.raised = foldKeys(s.raised)
+	}
+	return s.bind()
+}
+
+// load returns the most recently published state.
+func (c *Config) load() *state {
+	if s := c.current.Load(); s != nil {
+		return s
+	}
+	return &state{delim: defaultKeyDelimiter}
+}
+
+// Source identifies the layer a resolved value came from.
+type Source string
+
+const (
+	SourceDefault  Source = "default"
+	SourceConfig   Source = "config"
+	SourceEnv      Source = "env"
+	SourceProvider Source = "provider"
+	SourceOverride Source = "override"
+)
+
+// envName returns the environment variable that can override key.
+func (s *state) envName(key string) string {
+	if env, ok := s.envBindings[key]; ok {
+		return env
+	}
+	return prefixEnv(s.envPrefix, strings.ToUpper(strings.ReplaceAll(key, s.delim, "_")))
+}
+
+// envNames returns the environment variables that can override key, in
+// priority order: the bound or primary name, then the names under the
+// fallback prefixes and, when allowed for key, the unprefixed name.
+func (s *state) envNames(key string) []string {
+	if env, ok := s.envBindings[key]; ok {
+		return []string{env}
+	}
+	base := strings.ToUpper(strings.ReplaceAll(key, s.delim, "_"))
+	names := []string{prefixEnv(s.envPrefix, base)}
+	for _, prefix := range s.envFallback {
+		names = append(names, prefixEnv(prefix, base))
+	}
+	if matchKeyOrParent(s.envBare, key, s.delim) {
+		names = append(names, base)
+	}
+	return names
+}
+
+func prefixEnv(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+func (s *state) getEnv(key string) (any, bool) {
+	for _, name := range s.envNames(key) {
+		if v, ok := os.LookupEnv(name); ok {
+			if s.structured {
+				return parseStructuredEnv(v), true
+			}
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (s *state) get(key string) (any, bool) {
+	v, _, ok := s.resolve(key)
+	return v, ok
+}
+
+// resolve returns the effective value for key along with the layer it was
+// found in.
+func (s *state) resolve(key string) (any, Source, bool) {
+	if len(s.aliases) > 0 || s.fold {
+		return s.aliased(key, s.resolveCanonical)
+	}
+	return s.resolveCanonical(key)
+}
+
+// resolveCanonical resolves key, already following the aliases.
+func (s *state) resolveCanonical(key string) (any, Source, bool) {
+	if v, ok := s.getOverride(key); ok {
+		if _, isMap := v.(map[string]any); isMap {
+			base, _, _ := s.resolveLayers(key)
+			m, _ := base.(map[string]any)
+			v = s.overlay(key, m)
+		}
+		return v, SourceOverride, true
+	}
+	return s.resolveLayers(key)
+}
+
+// resolveLayers resolves key below the override layer.
+func (s *state) resolveLayers(key string) (any, Source, bool) {
+	if s.precedence != nil {
+		return s.resolveOrdered(key)
+	}
+	if s.automatic {
+		if v, ok := s.getEnv(key); ok {
+			if !matchKeyOrParent(s.locked, key, s.delim) {
+				return v, SourceEnv, true
+			}
+			if fv, ok := fetchValue(s.values, key, s.delim); ok {
+				s.locks.report(s.logger, key, string(SourceEnv))
+				return fv, SourceConfig, true
+			}
+			return v, SourceEnv, true
+		}
+	}
+	if v, ok := fetchValue(s.raised, key, s.delim); ok {
+		return v, SourceProvider, true
+	}
+	if v, ok := fetchValue(s.values, key, s.delim); ok {
+		return v, SourceConfig, true
+	}
+	if v, ok := s.getEnv(key); ok {
+		return v, SourceEnv, true
+	}
+	if v, ok := fetchValue(s.providers, key, s.delim); ok {
+		return v, SourceProvider, true
+	}
+	if v, ok := s.getDefault(key); ok {
+		return v, SourceDefault, true
+	}
+	return nil, "", false
+}
+
+// keys returns every leaf key known to the state, flattened with the key
+// delimiter and sorted. Lists are treated as leaves.
+func (s *state) keys() []string {
+	flat := make(map[string]any)
+	flattenInto("", s.defaults, flat, s.delim)
+	flattenInto("", s.providers, flat, s.delim)
+	flattenInto("", s.raised, flat, s.delim)
+	flattenInto("", s.values, flat, s.delim)
+	flattenInto("", s.overrides, flat, s.delim)
+	for key := range s.envBindings {
+		flat[key] = nil
+	}
+	for key := range s.computed {
+		flat[key] = nil
+	}
+	seen := make(map[string]bool, len(flat))
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		key = s.canonical(key)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// effective returns the resolved value of every known key, flattened.
+func (s *state) effective() map[string]any {
+	out := make(map[string]any)
+	for _, key := range s.keys() {
+		if v, ok := s.get(key); ok {
+			out[key] = v
+		}
+	}
+	return out
+}
+
+// settings returns the effective configuration as a nested map.
+func (s *state) settings() map[string]any {
+	out := make(map[string]any)
+	for _, key := range s.keys() {
+		if v, ok := s.get(key); ok {
+			setPath(out, strings.Split(key, s.delim), cloneValue(v))
+		}
+	}
+	return out
+}
+
+// flattenInto stores in out every leaf of value, keyed by its path below
+// prefix joined with sep.
+func flattenInto(prefix string, value any, out map[string]any, sep string) {
+	m, ok := value.(map[string]any)
+	if !ok || (len(m) == 0 && prefix != "") {
+		if prefix != "" {
+			out[prefix] = value
+		}
+		return
+	}
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + sep + k
+		}
+		flattenInto(key, v, out, sep)
+	}
+}
+
+func setPath(dst map[string]any, parts []string, value any) {
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := dst[part].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			dst[part] = next
+		}
+		dst = next
+	}
+	dst[parts[len(parts)-1]] = value
+}
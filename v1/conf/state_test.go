@@ -0,0 +1,112 @@
+package conf
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReadersSeeConsistentState(t *testing.T) {
+	c := New()
+	c.SetConfigType("yaml")
+	if err := c.ReadConfig(strings.NewReader("a: 1\nb: 1\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.load()
+	if err := c.ReadConfig(strings.NewReader("a: 2\nb: 2\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.get("a"); got != 1 {
+		t.Fatalf("expected previously loaded state to stay at a=1, got %v", got)
+	}
+	if got := c.GetInt("a"); got != 2 {
+		t.Fatalf("expected a=2 after reload, got %d", got)
+	}
+}
+
+// rwMutexConfig mirrors the previous read path, where every getter took the
+// shared RWMutex, and serves as a baseline for the contention benchmarks.
+type rwMutexConfig struct {
+	mu sync.RWMutex
+	s  *state
+}
+
+func (r *rwMutexConfig) get(key string) (any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.s.get(key)
+}
+
+func (r *rwMutexConfig) reload(s *state) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.s = s
+}
+
+func benchmarkValues() map[string]any {
+	return map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 8080},
+	}
+}
+
+func BenchmarkGetContended(b *testing.B) {
+	b.Run("atomic", func(b *testing.B) {
+		c := New()
+		c.MergeConfigMap(benchmarkValues())
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					c.MergeConfigMap(benchmarkValues())
+				}
+			}
+		}()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = c.GetInt("server.port")
+			}
+		})
+	})
+
+	b.Run("rwmutex", func(b *testing.B) {
+		c := New()
+		c.MergeConfigMap(benchmarkValues())
+		r := &rwMutexConfig{s: c.load()}
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					c.MergeConfigMap(benchmarkValues())
+					r.reload(c.load())
+				}
+			}
+		}()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = r.get("server.port")
+			}
+		})
+	})
+}
+
+func BenchmarkGetUncontended(b *testing.B) {
+	c := New()
+	c.MergeConfigMap(benchmarkValues())
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = c.GetInt("server.port")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package conf
+
+import "strings"
+
+// Sub returns a Config rooted at key, e.g. Sub("database"), so a component
+// can be handed only its section: sub.GetString("host") reads
+// "database.host". The defaults, config values, provider values and
+// overrides under key are copied, environment variables keep their names,
+// APP_DATABASE_HOST still overriding "host", and secret, lock and metadata
+// declarations under key carry over. The result is a copy: later changes to
+// c are not reflected. Sub returns nil when key does not hold a map.
+func (c *Config) Sub(key string) *Config {
+	if _, err := c.GetStringMapE(key); err != nil {
+		return nil
+	}
+	sub := New()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sep := c.delimLocked()
+	if c.foldKeys {
+		key = strings.ToLower(key)
+	}
+	prefix := key + sep
+	sub.keyDelim = c.keyDelim
+	sub.foldKeys = c.foldKeys
+	sub.defaults = subtreeOf(c.defaults, key, sep)
+	sub.defaultFuncs = make(map[string]func(*Config) any)
+	view := c.load().view
+	for k, fn := range c.defaultFuncs {
+		if rel, ok := strings.CutPrefix(k, prefix); ok {
+			sub.defaultFuncs[rel] = func(*Config) any { return fn(view) }
+		}
+	}
+	sub.values = subtreeOf(c.values, key, sep)
+	sub.providerValues = subtreeOf(c.providerValues, key, sep)
+	sub.raisedValues = subtreeOf(c.raisedValues, key, sep)
+	sub.configWeight = c.configWeight
+	sub.overrides = subtreeOf(c.overrides, key, sep)
+
+	base := strings.ToUpper(strings.ReplaceAll(key, sep, "_"))
+	sub.envPrefix = prefixEnv(c.envPrefix, base)
+	for _, p := range c.envFallback {
+		sub.envFallback = append(sub.envFallback, prefixEnv(p, base))
+	}
+	for k, env := range c.envBindings {
+		if rel, ok := strings.CutPrefix(k, prefix); ok {
+			sub.envBindings[rel] = env
+		}
+	}
+	sub.automatic = c.automatic
+	sub.structuredEnv = c.structuredEnv
+	sub.precedence = c.precedence
+	sub.coercion = c.coercion
+	sub.parseOptions = c.parseOptions
+	sub.logger = c.logger
+	sub.locked = subPatterns(c.locked, prefix)
+	sub.secrets = subPatterns(c.secrets, prefix)
+	for k, m := range c.meta {
+		if rel, ok := strings.CutPrefix(k, prefix); ok {
+			if sub.meta == nil {
+				sub.meta = make(map[string]Meta)
+			}
+			sub.meta[rel] = m
+		}
+	}
+	sub.publishLocked()
+	return sub
+}
+
+// subtreeOf returns a copy of the map stored under key in m, including the
+// entries stored with literal delimited keys below it.
+func subtreeOf(m map[string]any, key, sep string) map[string]any {
+	out := make(map[string]any)
+	if v, ok := fetchValue(m, key, sep); ok {
+		if nested, ok := v.(map[string]any); ok {
+			out = cloneMap(nested)
+		}
+	}
+	for k, v := range m {
+		if rel, ok := strings.CutPrefix(k, key+sep); ok {
+			out[rel] = cloneValue(v)
+		}
+	}
+	return out
+}
+
+// subPatterns returns the patterns starting with prefix, relative to it.
+func subPatterns(patterns []string, prefix string) []string {
+	var out []string
+	for _, p := range patterns {
+		if rel, ok := strings.CutPrefix(p, prefix); ok {
+			out = append(out, rel)
+		}
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package conf
+
+import "testing"
+
+func TestSub(t *testing.T) {
+	t.Setenv("APP_DATABASE_USER", "admin")
+	c := New()
+	c.SetEnvPrefix("APP")
+	c.SetDefault("database.port", 5432)
+	c.SetDefaultFunc("database.pool", func(*Config) any { return 10 })
+	c.MergeConfigMap(map[string]any{
+		"database": map[string]any{"host": "db", "password": "hunter2"},
+		"server":   map[string]any{"port": 8080},
+		"name":     "app",
+	})
+	c.MarkSecret("database.password")
+
+	db := c.Sub("database")
+	if db == nil {
+		t.Fatalf("expected a sub configuration")
+	}
+	if got := db.GetString("host"); got != "db" {
+		t.Fatalf("expected host from the subtree, got %q", got)
+	}
+	if db.GetInt("port") != 5432 || db.GetInt("pool") != 10 {
+		t.Fatalf("expected defaults under the subtree, got %d and %d", db.GetInt("port"), db.GetInt("pool"))
+	}
+	if got := db.GetString("user"); got != "admin" {
+		t.Fatalf("expected env names to be preserved, got %q", got)
+	}
+	if !db.IsSecret("password") {
+		t.Fatalf("expected secrets to carry over")
+	}
+	if db.IsSet("server.port") || db.IsSet("name") {
+		t.Fatalf("expected keys outside the subtree to be left out")
+	}
+	if c.Sub("name") != nil || c.Sub("missing") != nil {
+		t.Fatalf("expected nil for keys not holding a map")
+	}
+}
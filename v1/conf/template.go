@@ -0,0 +1,92 @@
+package conf
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+// GetTemplate parses the value for the key as a text/template named after
+// the key, with funcs available to it. It returns nil when the key is missing
+// or the template does not parse.
+func (c *Config) GetTemplate(key string, funcs template.FuncMap) *template.Template {
+	t, err := c.GetTemplateE(key, funcs)
+	c.noteCoercion(err)
+	return t
+}
+
+// GetTemplateE is like GetTemplate but reports missing keys and parse
+// errors.
+func (c *Config) GetTemplateE(key string, funcs template.FuncMap) (*template.Template, error) {
+	text, err := c.templateText(key)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(key).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", &ConversionError{Key: key, Value: text, Target: "template"}, err)
+	}
+	return t, nil
+}
+
+// GetHTMLTemplate is the html/template variant of GetTemplate, escaping the
+// data it renders.
+func (c *Config) GetHTMLTemplate(key string, funcs htmltemplate.FuncMap) *htmltemplate.Template {
+	t, err := c.GetHTMLTemplateE(key, funcs)
+	c.noteCoercion(err)
+	return t
+}
+
+// GetHTMLTemplateE is like GetHTMLTemplate but reports missing keys and
+// parse errors.
+func (c *Config) GetHTMLTemplateE(key string, funcs htmltemplate.FuncMap) (*htmltemplate.Template, error) {
+	text, err := c.templateText(key)
+	if err != nil {
+		return nil, err
+	}
+	t, err := htmltemplate.New(key).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", &ConversionError{Key: key, Value: text, Target: "template"}, err)
+	}
+	return t, nil
+}
+
+// RequireTemplate makes key a template that must parse with funcs: the
+// current value is checked immediately and a validator rejects any later
+// read, reload or merge that would break it, so broken templates fail at
+// startup rather than when a message is first rendered. A missing key is
+// accepted.
+func (c *Config) RequireTemplate(key string, funcs template.FuncMap) error {
+	check := func(s Snapshot) error {
+		v, ok := s.Get(key)
+		if !ok {
+			return nil
+		}
+		text, ok := v.(string)
+		if !ok {
+			return &ConversionError{Key: key, Value: v, Target: "template"}
+		}
+		if _, err := template.New(key).Funcs(funcs).Parse(text); err != nil {
+			return fmt.Errorf("%w: %v", &ConversionError{Key: key, Value: text, Target: "template"}, err)
+		}
+		return nil
+	}
+	if err := check(c.Snapshot()); err != nil {
+		return err
+	}
+	c.AddValidator(check)
+	return nil
+}
+
+// templateText returns the string value of key for the template getters.
+func (c *Config) templateText(key string) (string, error) {
+	v, ok := c.load().read(key)
+	if !ok {
+		return "", notFound(key)
+	}
+	text, ok := v.(string)
+	if !ok {
+		return "", &ConversionError{Key: key, Value: v, Target: "template"}
+	}
+	return text, nil
+}
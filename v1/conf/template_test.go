@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestGetTemplate(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"messages": map[string]any{
+		"welcome": "Hello {{ upper .Name }}",
+		"page":    "<p>{{ .Name }}</p>",
+	}})
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+
+	var out strings.Builder
+	if err := c.GetTemplate("messages.welcome", funcs).Execute(&out, map[string]string{"Name": "ada"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "Hello ADA" {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	out.Reset()
+	if err := c.GetHTMLTemplate("messages.page", nil).Execute(&out, map[string]string{"Name": "<b>"}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "<p>&lt;b&gt;</p>" {
+		t.Fatalf("expected escaped output, got %q", out.String())
+	}
+	if _, err := c.GetTemplateE("messages.welcome", nil); err == nil {
+		t.Fatalf("expected parse error without the upper function")
+	}
+
+	if err := c.RequireTemplate("messages.welcome", funcs); err != nil {
+		t.Fatal(err)
+	}
+	c.MergeConfigMap(map[string]any{"messages": map[string]any{"welcome": "Hello {{ .Name"}})
+	if got := c.GetString("messages.welcome"); got != "Hello {{ upper .Name }}" {
+		t.Fatalf("expected broken template to be rejected, got %q", got)
+	}
+	var convErr *ConversionError
+	c.MergeConfigMap(map[string]any{"messages": map[string]any{"other": "{{"}})
+	if err := c.RequireTemplate("messages.other", nil); !errors.As(err, &convErr) {
+		t.Fatalf("expected current broken template to be reported, got %v", err)
+	}
+}
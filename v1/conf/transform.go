@@ -0,0 +1,79 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type transform struct {
+	pattern string
+	fn      func(string) string
+}
+
+// Transform registers fn to normalize the values of the keys matching
+// pattern, which may use "*" wildcards and also covers the keys below a
+// matching one. It runs on string values and on the string elements of
+// lists, after the value has been resolved and before it is converted by the
+// getter, so normalization is written once:
+//
+//	cfg.Transform("paths.*", strings.TrimSpace)
+//	cfg.Transform("paths.*", conf.ExpandHome)
+//
+// Transforms run in registration order.
+func (c *Config) Transform(pattern string, fn func(string) string) {
+	if pattern == "" || fn == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mustNotBeFrozenLocked()
+	c.transforms = append(c.transforms, transform{pattern: pattern, fn: fn})
+	c.publishLocked()
+}
+
+// ExpandHome replaces a leading "~" with the home directory of the current
+// user. Other values, and all values when the home directory is unknown, are
+// returned unchanged.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if path == "~" {
+		return home
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// transformed applies the transforms matching key to v.
+func (s *state) transformed(key string, v any) any {
+	for _, t := range s.transforms {
+		if !matchKeyOrParent([]string{t.pattern}, key, s.delim) {
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			v = t.fn(val)
+		case []string:
+			out := make([]string, len(val))
+			for i, item := range val {
+				out[i] = t.fn(item)
+			}
+			v = out
+		case []any:
+			out := make([]any, len(val))
+			for i, item := range val {
+				if str, ok := item.(string); ok {
+					item = t.fn(str)
+				}
+				out[i] = item
+			}
+			v = out
+		}
+	}
+	return v
+}
@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	c := New()
+	c.MergeConfigMap(map[string]any{
+		"paths": map[string]any{
+			"cache":  "  ~/cache ",
+			"search": []any{"~/a", " /b"},
+		},
+		"name": "  spaced  ",
+	})
+	c.Transform("paths.*", strings.TrimSpace)
+	c.Transform("paths", ExpandHome)
+
+	if got := c.GetString("paths.cache"); got != filepath.Join(home, "cache") {
+		t.Fatalf("expected trimmed and expanded path, got %q", got)
+	}
+	search := c.GetStringSlice("paths.search")
+	if len(search) != 2 || search[0] != filepath.Join(home, "a") || search[1] != "/b" {
+		t.Fatalf("expected list elements to be transformed, got %v", search)
+	}
+	if got := c.GetString("name"); got != "  spaced  " {
+		t.Fatalf("expected other keys to be untouched, got %q", got)
+	}
+	if ExpandHome("/abs/~") != "/abs/~" || ExpandHome("~") != home {
+		t.Fatalf("unexpected ExpandHome result")
+	}
+}
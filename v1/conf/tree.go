@@ -0,0 +1,107 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type treeNode struct {
+	children map[string]*treeNode
+	key      string
+	leaf     bool
+}
+
+// Tree renders the effective configuration as an indented hierarchy, one
+// node per line. Leaves carry their value, type and source layer, e.g.
+//
+//	server
+//	├── host = "localhost"  (string, config)
+//	└── port = 8080  (int, env)
+//
+// Values of secret keys are masked, see MarkSecret.
+func (c *Config) Tree() string {
+	s := c.load()
+	root := &treeNode{}
+	for _, key := range s.keys() {
+		node := root
+		for _, part := range strings.Split(key, s.delim) {
+			if node.children == nil {
+				node.children = make(map[string]*treeNode)
+			}
+			next, ok := node.children[part]
+			if !ok {
+				next = &treeNode{}
+				node.children[part] = next
+			}
+			node = next
+		}
+		node.key = key
+		node.leaf = true
+	}
+
+	var b strings.Builder
+	writeTree(&b, s, c.secretSet(), root, "", true)
+	return b.String()
+}
+
+func writeTree(b *strings.Builder, s *state, secrets secretSet, node *treeNode, indent string, top bool) {
+	names := make([]string, 0, len(node.children))
+	for name := range node.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		child := node.children[name]
+		last := i == len(names)-1
+		branch, nextIndent := "├── ", indent+"│   "
+		if last {
+			branch, nextIndent = "└── ", indent+"    "
+		}
+		if top {
+			branch, nextIndent = "", ""
+		}
+		b.WriteString(indent + branch + name)
+		if child.leaf {
+			if v, src, ok := s.resolve(child.key); ok {
+				text := redactedValue
+				if !secrets.has(child.key) {
+					text = formatValue(secrets.redact(child.key, v, redactedValue))
+				}
+				fmt.Fprintf(b, " = %s  (%s, %s)", text, typeName(v), src)
+			}
+		}
+		b.WriteString("\n")
+		writeTree(b, s, secrets, child, nextIndent, false)
+	}
+}
+
+func typeName(v any) string {
+	switch val := v.(type) {
+	case json.Number:
+		if isIntegerNumber(val) {
+			return "int"
+		}
+		return "float"
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "int"
+	case float32, float64:
+		return "float"
+	case time.Duration:
+		return "duration"
+	case []any, []string, []int:
+		return "list"
+	case map[string]any:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
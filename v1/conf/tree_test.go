@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	c := New()
+	c.SetDefault("log.level", "info")
+	c.SetDefault("db.password", "hunter2")
+	c.MergeConfigMap(map[string]any{
+		"server": map[string]any{"host": "localhost", "port": 8080},
+	})
+
+	want := strings.Join([]string{
+		"db",
+		"└── password = ******  (string, default)",
+		"log",
+		"└── level = \"info\"  (string, default)",
+		"server",
+		"├── host = \"localhost\"  (string, config)",
+		"└── port = 8080  (int, config)",
+		"",
+	}, "\n")
+	if got := c.Tree(); got != want {
+		t.Fatalf("expected tree\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestTreeRedactsSecretsInLists(t *testing.T) {
+	c := New()
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"users": []any{
+		map[string]any{"name": "a", "password": "hunter2"},
+	}}})
+	if got := c.Tree(); strings.Contains(got, "hunter2") {
+		t.Fatalf("expected secrets inside lists to be redacted, got\n%s", got)
+	}
+}
@@ -0,0 +1,127 @@
+package conf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError reports every required key that could not be resolved and
+// every declared key whose value violates its metadata.
+type ValidationError struct {
+	Missing []string
+	Invalid []InvalidKey
+}
+
+// InvalidKey describes a declared key whose value failed validation.
+type InvalidKey struct {
+	Key    string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	var parts []string
+	if len(e.Missing) > 0 {
+		parts = append(parts, "missing required keys: "+strings.Join(e.Missing, ", "))
+	}
+	for _, inv := range e.Invalid {
+		parts = append(parts, fmt.Sprintf("invalid %s: %s", inv.Key, inv.Reason))
+	}
+	return "conf: " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns one error per problem: an error wrapping ErrKeyNotFound for
+// every missing key and an InvalidKey for every invalid one.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Missing)+len(e.Invalid))
+	for _, key := range e.Missing {
+		errs = append(errs, notFound(key))
+	}
+	for _, inv := range e.Invalid {
+		errs = append(errs, inv)
+	}
+	return errs
+}
+
+func (k InvalidKey) Error() string {
+	return fmt.Sprintf("conf: invalid %s: %s", k.Key, k.Reason)
+}
+
+// Require declares keys that must resolve from some source once all of them
+// have been merged. Requirements are checked by Validate.
+func (c *Config) Require(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if key == "" || containsString(c.required, key) {
+			continue
+		}
+		c.required = append(c.required, key)
+	}
+}
+
+// Validate checks that every required key resolves and that declared keys
+// satisfy their metadata. All problems are reported at once through a
+// *ValidationError.
+func (c *Config) Validate() error {
+	c.mu.RLock()
+	rules := c.rulesLocked()
+	c.mu.RUnlock()
+	return rules.validate(c.load())
+}
+
+// rules is a copy of the required keys and declared metadata, taken under
+// c.mu so that a state can be resolved and validated without holding the
+// lock.
+type rules struct {
+	required []string
+	meta     map[string]Meta
+}
+
+func (c *Config) rulesLocked() rules {
+	r := rules{
+		required: append([]string(nil), c.required...),
+		meta:     make(map[string]Meta, len(c.meta)),
+	}
+	for key, meta := range c.meta {
+		r.meta[key] = meta
+	}
+	return r
+}
+
+// validate checks s against the required keys and declared metadata.
+func (r rules) validate(s *state) error {
+	verr := &ValidationError{}
+	for _, key := range r.required {
+		if _, ok := s.get(key); !ok {
+			verr.Missing = append(verr.Missing, key)
+		}
+	}
+	keys := make([]string, 0, len(r.meta))
+	for key := range r.meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v, ok := s.get(key)
+		if !ok {
+			continue
+		}
+		if reason := r.meta[key].check(s.parseOptions, v); reason != "" {
+			verr.Invalid = append(verr.Invalid, InvalidKey{Key: key, Reason: reason})
+		}
+	}
+	if len(verr.Missing) > 0 || len(verr.Invalid) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
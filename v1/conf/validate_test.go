@@ -0,0 +1,32 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRequireAndValidate(t *testing.T) {
+	c := New()
+	c.SetEnvPrefix("VALIDATE")
+	c.Require("server.port", "database.dsn", "log_level")
+	c.SetDefault("log_level", "info")
+	c.MergeConfigMap(map[string]any{
+		"server": map[string]any{"port": 8080},
+	})
+
+	err := c.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(verr.Missing) != 1 || verr.Missing[0] != "database.dsn" {
+		t.Fatalf("expected only database.dsn to be missing, got %#v", verr.Missing)
+	}
+
+	os.Setenv("VALIDATE_DATABASE_DSN", "postgres://localhost")
+	defer os.Unsetenv("VALIDATE_DATABASE_DSN")
+	if err := c.Validate(); err != nil {
+		t.Fatalf("expected env to satisfy requirement, got %v", err)
+	}
+}
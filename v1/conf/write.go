@@ -0,0 +1,154 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Describe attaches a comment to key. Comments are written above the key by
+// WriteConfig, WriteConfigAs and WriteExample for the formats supporting
+// them, and take precedence over the description declared in Meta. Keys of
+// whole sections can be described too. An empty comment removes it.
+func (c *Config) Describe(key, comment string) {
+	if key == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if comment == "" {
+		delete(c.comments, key)
+		return
+	}
+	if c.comments == nil {
+		c.comments = make(map[string]string)
+	}
+	c.comments[key] = comment
+}
+
+// WriteOption customizes how WriteConfig and WriteConfigAs create the file.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	mode     os.FileMode
+	hasMode  bool
+	dirMode  os.FileMode
+	mkdir    bool
+	uid, gid int
+	chown    bool
+}
+
+// WithFileMode sets the permissions of the written file, applied regardless
+// of the umask and also to a file that already exists. Without it a new file
+// gets 0600 when it holds secret keys and 0644 otherwise, and an existing file
+// keeps its permissions, unless secret keys are written to a file others can
+// access, which is restricted to 0600 first.
+func WithFileMode(mode os.FileMode) WriteOption {
+	return func(o *writeOptions) {
+		o.mode = mode.Perm()
+		o.hasMode = true
+	}
+}
+
+// WithCreateDirs creates the missing parent directories of the file with the
+// given permissions.
+func WithCreateDirs(mode os.FileMode) WriteOption {
+	return func(o *writeOptions) {
+		o.dirMode = mode.Perm()
+		o.mkdir = true
+	}
+}
+
+// WithOwner changes the owner of the written file, which normally requires
+// running as root. A negative uid or gid leaves that id unchanged.
+func WithOwner(uid, gid int) WriteOption {
+	return func(o *writeOptions) {
+		o.uid, o.gid = uid, gid
+		o.chown = true
+	}
+}
+
+// WriteConfig writes the effective configuration to the config file in use,
+// in the format given by its extension.
+func (c *Config) WriteConfig(opts ...WriteOption) error {
+	c.mu.RLock()
+	file := c.file
+	c.mu.RUnlock()
+	if file == "" {
+		return errors.New("config file not set")
+	}
+	return c.WriteConfigAs(file, opts...)
+}
+
+// WriteConfigAs writes the effective configuration to path, in the format
+// given by its extension, with the comments registered through Describe and
+// the sections registered through EncryptSection encrypted. An existing file
+// is backed up first when SetBackupCount is in effect.
+func (c *Config) WriteConfigAs(path string, opts ...WriteOption) error {
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// Computed defaults may call back into c, so they are resolved before
+	// taking the lock.
+	values := c.load().settings()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	comments := make(map[string][]string, len(c.comments))
+	for key, comment := range c.comments {
+		comments[key] = strings.Split(comment, "\n")
+	}
+	secret := len(c.secretKeysLocked(values)) > 0
+	if !o.hasMode {
+		o.mode = 0o644
+		if secret {
+			o.mode = 0o600
+		}
+	}
+	if err := c.encryptSectionsLocked(values); err != nil {
+		return err
+	}
+	data, err := c.encodeCommentedLocked(filepath.Ext(path), values, comments)
+	if err != nil {
+		return err
+	}
+	if o.mkdir {
+		if err := os.MkdirAll(filepath.Dir(path), o.dirMode); err != nil {
+			return err
+		}
+	}
+	info, err := os.Stat(path)
+	created := os.IsNotExist(err)
+	if err := c.backupLocked(path); err != nil {
+		return err
+	}
+	// An existing file is restricted before the secrets reach it.
+	if err == nil && (o.hasMode || (secret && info.Mode().Perm()&^o.mode != 0)) {
+		if err := os.Chmod(path, o.mode); err != nil {
+			return err
+		}
+	}
+	if err := c.writeFileLocked(path, data, o.mode); err != nil {
+		return err
+	}
+	if created {
+		if err := os.Chmod(path, o.mode); err != nil {
+			return err
+		}
+	}
+	if o.chown {
+		return os.Chown(path, o.uid, o.gid)
+	}
+	return nil
+}
+
+// commentsLocked returns the comment lines of key in an example file: its
+// Describe comment or description, followed by a summary of its metadata.
+func (c *Config) commentsLocked(key string) []string {
+	meta := c.meta[key]
+	if comment, ok := c.comments[key]; ok {
+		meta.Desc = comment
+	}
+	return meta.comments()
+}
@@ -0,0 +1,112 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteConfigWithComments(t *testing.T) {
+	dir := t.TempDir()
+	for _, format := range []string{"yaml", "toml"} {
+		path := filepath.Join(dir, "config."+format)
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		c.SetConfigFile(path)
+		if err := c.ReadInConfig(); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		c.MergeConfigMap(map[string]any{"server": map[string]any{"port": 8080}})
+		c.Describe("server", "HTTP server settings.")
+		c.Describe("server.port", "Port to listen on.")
+		if err := c.WriteConfig(); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{"# HTTP server settings.", "# Port to listen on."} {
+			if !strings.Contains(string(data), want) {
+				t.Fatalf("%s: expected %q in\n%s", format, want, data)
+			}
+		}
+		other := New()
+		other.SetConfigFile(path)
+		if err := other.ReadInConfig(); err != nil || other.GetInt("server.port") != 8080 {
+			t.Fatalf("%s: expected port to round trip, got %v in\n%s", format, err, data)
+		}
+	}
+
+	if err := New().WriteConfig(); err == nil {
+		t.Fatalf("expected error without a config file")
+	}
+}
+
+func TestWriteConfigFileModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not meaningful on windows")
+	}
+	dir := t.TempDir()
+	c := New()
+	c.MergeConfigMap(map[string]any{"db": map[string]any{"password": "hunter2"}})
+
+	secret := filepath.Join(dir, "nested", "app.yaml")
+	if err := c.WriteConfigAs(secret, WithCreateDirs(0o700), WithOwner(os.Getuid(), -1)); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 for a file with secrets, got %04o", info.Mode().Perm())
+	}
+
+	if err := c.WriteConfigAs(secret, WithFileMode(0o640)); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(secret); info.Mode().Perm() != 0o640 {
+		t.Fatalf("expected explicit mode on an existing file, got %04o", info.Mode().Perm())
+	}
+	if err := c.WriteConfigAs(secret); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(secret); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected an existing file receiving secrets to be restricted, got %04o", info.Mode().Perm())
+	}
+
+	users := New()
+	users.MergeConfigMap(map[string]any{"db": map[string]any{"users": []any{
+		map[string]any{"name": "a", "password": "hunter2"},
+	}}})
+	listed := filepath.Join(dir, "users.yaml")
+	if err := users.WriteConfigAs(listed); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(listed); info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 for a file with secrets inside lists, got %04o", info.Mode().Perm())
+	}
+
+	plain := filepath.Join(dir, "plain.yaml")
+	if err := New().WriteConfigAs(plain); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(plain); info.Mode().Perm() != 0o644 {
+		t.Fatalf("expected 0644 without secrets, got %04o", info.Mode().Perm())
+	}
+	if err := os.Chmod(plain, 0o664); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().WriteConfigAs(plain); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(plain); info.Mode().Perm() != 0o664 {
+		t.Fatalf("expected existing mode to be kept without secrets, got %04o", info.Mode().Perm())
+	}
+}